@@ -0,0 +1,243 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// AllocStopAction stops allocations so the scheduler replaces them.
+type AllocStopAction struct{}
+
+// Name returns the action identifier.
+func (a *AllocStopAction) Name() string {
+	return "alloc-stop"
+}
+
+// Description returns a human-readable description.
+func (a *AllocStopAction) Description() string {
+	return "Stop selected allocations through the API, forcing a reschedule"
+}
+
+// Execute stops the selected allocations.
+func (a *AllocStopAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	client, allocs, err := selectAllocs(ctx, actx, args)
+	if err != nil {
+		return err
+	}
+	return forEachAlloc(actx, allocs, func(alloc nomad.Allocation) error {
+		return client.StopAllocation(ctx, alloc.ID)
+	})
+}
+
+// Rollback is a no-op; the scheduler replaces stopped allocations.
+func (a *AllocStopAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	return nil
+}
+
+// AllocRestartAction restarts tasks in place.
+type AllocRestartAction struct{}
+
+// Name returns the action identifier.
+func (a *AllocRestartAction) Name() string {
+	return "alloc-restart"
+}
+
+// Description returns a human-readable description.
+func (a *AllocRestartAction) Description() string {
+	return "Restart the tasks of selected allocations in place"
+}
+
+// Execute restarts the selected task, or all tasks, of each allocation.
+func (a *AllocRestartAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	client, allocs, err := selectAllocs(ctx, actx, args)
+	if err != nil {
+		return err
+	}
+	task := params.String(args, "task", "")
+	return forEachAlloc(actx, allocs, func(alloc nomad.Allocation) error {
+		return client.RestartAllocation(ctx, alloc.ID, task)
+	})
+}
+
+// Rollback is a no-op; restarted tasks come back on their own.
+func (a *AllocRestartAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	return nil
+}
+
+// AllocKillAction kills allocation tasks, through the API or on the host.
+type AllocKillAction struct{}
+
+// Name returns the action identifier.
+func (a *AllocKillAction) Name() string {
+	return "alloc-kill"
+}
+
+// Description returns a human-readable description.
+func (a *AllocKillAction) Description() string {
+	return "Signal allocation tasks through the API, or kill their processes or VMs on the host (hard)"
+}
+
+// Execute signals the tasks. With hard=true the API is bypassed and every
+// process on the client referencing the allocation (task processes,
+// containers, qemu) is killed over SSH, so Nomad only learns of it when the
+// task exits underneath it.
+func (a *AllocKillAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	signal := strings.TrimPrefix(strings.ToUpper(params.String(args, "signal", "KILL")), "SIG")
+	hard := params.Bool(args, "hard", false)
+
+	client, allocs, err := selectAllocs(ctx, actx, args)
+	if err != nil {
+		return err
+	}
+	actx.Details["signal"] = signal
+	actx.Details["hard"] = hard
+
+	if !hard {
+		task := params.String(args, "task", "")
+		return forEachAlloc(actx, allocs, func(alloc nomad.Allocation) error {
+			return client.SignalAllocation(ctx, alloc.ID, task, "SIG"+signal)
+		})
+	}
+
+	pids := make(map[string]string)
+	actx.Details["pids"] = pids
+	return forEachAlloc(actx, allocs, func(alloc nomad.Allocation) error {
+		node, err := allocHost(ctx, actx, client, alloc)
+		if err != nil {
+			return err
+		}
+		out, err := runOnNode(ctx, actx.Driver, *node, killAllocScript(alloc.ID, signal))
+		if err != nil {
+			return fmt.Errorf("killing processes on %s: %w", node.Name, err)
+		}
+		out = strings.Join(strings.Fields(out), " ")
+		if out == "" {
+			return fmt.Errorf("no processes for allocation on %s", node.Name)
+		}
+		pids[alloc.ID] = node.Name + ": " + out
+		return nil
+	})
+}
+
+// Rollback is a no-op; Nomad restarts or reschedules killed tasks.
+func (a *AllocKillAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	return nil
+}
+
+// killAllocScript kills every process whose environment or command line
+// mentions the allocation ID, except the Nomad agent and its helpers, and
+// prints the PIDs it signalled. The ID is split in the script so the shell
+// running it does not match itself.
+func killAllocScript(allocID, signal string) string {
+	half := len(allocID) / 2
+	script := fmt.Sprintf(`a=%[1]s; b=%[2]s; id=$a$b
+for f in /proc/[0-9]*/environ /proc/[0-9]*/cmdline; do
+  grep -qsF $id "$f" || continue
+  p=${f#/proc/}; p=${p%%%%/*}
+  case $(readlink /proc/$p/exe) in */nomad) continue;; esac
+  echo $p
+done | sort -un | while read p; do kill -%[3]s $p 2>/dev/null && echo $p; done`, allocID[:half], allocID[half:], signal)
+	return fmt.Sprintf("sh -c '%s'", script)
+}
+
+// selectAllocs picks running allocations matching the job, group and task
+// selectors, or the explicit alloc IDs. count limits a random sample and
+// accepts "all".
+func selectAllocs(ctx context.Context, actx *driver.ActionContext, args map[string]any) (*nomad.Client, []nomad.Allocation, error) {
+	client, err := leaderClient(ctx, actx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ids := params.StringSlice(args, "alloc"); len(ids) > 0 {
+		var allocs []nomad.Allocation
+		for _, id := range ids {
+			alloc, err := client.Allocation(ctx, id)
+			if err != nil {
+				return nil, nil, fmt.Errorf("reading allocation %s: %w", id, err)
+			}
+			allocs = append(allocs, *alloc)
+		}
+		return client, allocs, nil
+	}
+
+	job := params.String(args, "job", "")
+	if job == "" {
+		return nil, nil, fmt.Errorf("job or alloc is required")
+	}
+	group := params.String(args, "group", "")
+	task := params.String(args, "task", "")
+
+	all, err := client.JobAllocations(ctx, job, params.String(args, "namespace", ""))
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing allocations of %s: %w", job, err)
+	}
+
+	var candidates []nomad.Allocation
+	for _, alloc := range all {
+		if alloc.ClientStatus != nomad.AllocClientStatusRunning || alloc.DesiredStatus != "run" {
+			continue
+		}
+		if group != "" && alloc.TaskGroup != group {
+			continue
+		}
+		if _, ok := alloc.TaskStates[task]; task != "" && !ok {
+			continue
+		}
+		candidates = append(candidates, alloc)
+	}
+	if len(candidates) == 0 {
+		return nil, nil, fmt.Errorf("no running allocations match job=%s group=%s task=%s", job, group, task)
+	}
+
+	count := len(candidates)
+	if c := params.String(args, "count", "1"); c != "all" {
+		count = min(params.Int(args, "count", 1), len(candidates))
+	}
+	if count < 1 {
+		return nil, nil, fmt.Errorf("count must be at least 1 or all")
+	}
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	return client, candidates[:count], nil
+}
+
+// forEachAlloc applies fn to each allocation, recording the IDs it
+// succeeded on in State so assertions can follow them.
+func forEachAlloc(actx *driver.ActionContext, allocs []nomad.Allocation, fn func(nomad.Allocation) error) error {
+	actx.State[driver.StateFaultTime] = time.Now()
+
+	var done []string
+	var errs []error
+	for _, alloc := range allocs {
+		if err := fn(alloc); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", alloc.ID, err))
+			continue
+		}
+		done = append(done, alloc.ID)
+	}
+
+	if len(done) > 0 {
+		actx.State[driver.StateAllocIDs] = done
+	}
+	actx.Details["allocs"] = done
+	return errors.Join(errs...)
+}
+
+// allocHost maps an allocation to the discovered client node running it.
+func allocHost(ctx context.Context, actx *driver.ActionContext, client *nomad.Client, alloc nomad.Allocation) (*driver.Node, error) {
+	node, err := client.Node(ctx, alloc.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("reading node of allocation %s: %w", alloc.ID, err)
+	}
+	return actx.Cluster.NodeByIP(node.IP())
+}
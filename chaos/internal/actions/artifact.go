@@ -0,0 +1,230 @@
+package actions
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+const (
+	// artifactUnit runs the corrupting HTTP interposer on the node.
+	artifactUnit = "chaos-artifact"
+	// artifactScript is where the interposer is written on the node.
+	artifactScript = "/run/chaos-artifact.py"
+	// artifactComment tags the iptables rules added by artifact-outage.
+	artifactComment = "chaos-artifact"
+)
+
+// interposerSource answers every GET with random bytes and a 200, so
+// go-getter accepts the download and the corruption surfaces later
+// (checksum mismatch, or the driver failing to boot the image).
+const interposerSource = `import os, sys
+from http.server import BaseHTTPRequestHandler, ThreadingHTTPServer
+
+SIZE = int(sys.argv[2])
+
+class Handler(BaseHTTPRequestHandler):
+    def do_HEAD(self):
+        self.send_response(200)
+        self.send_header("Content-Length", str(SIZE))
+        self.end_headers()
+
+    def do_GET(self):
+        self.do_HEAD()
+        left = SIZE
+        while left > 0:
+            chunk = os.urandom(min(left, 65536))
+            self.wfile.write(chunk)
+            left -= len(chunk)
+
+ThreadingHTTPServer(("127.0.0.1", int(sys.argv[1])), Handler).serve_forever()
+`
+
+// ArtifactOutageAction breaks an artifact source as seen from clients.
+type ArtifactOutageAction struct{}
+
+// Name returns the action identifier.
+func (a *ArtifactOutageAction) Name() string {
+	return "artifact-outage"
+}
+
+// Description returns a human-readable description.
+func (a *ArtifactOutageAction) Description() string {
+	return "Block, slow down or corrupt downloads from an artifact host:port on clients"
+}
+
+// Execute applies the fault on each node:
+//   - block: reject TCP connections to the source
+//   - slow: delay and rate-limit traffic to and from the port with netem
+//   - corrupt: redirect connections to a local server returning random bytes
+func (a *ArtifactOutageAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	mode := params.String(args, "mode", "block")
+	if mode != "block" && mode != "slow" && mode != "corrupt" {
+		return fmt.Errorf("invalid mode %q: must be block, slow or corrupt", mode)
+	}
+
+	host, portStr, err := net.SplitHostPort(params.String(args, "source", "localhost:8888"))
+	if err != nil {
+		return fmt.Errorf("invalid source: %w", err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return fmt.Errorf("invalid source port %q", portStr)
+	}
+
+	selectors := params.StringSlice(args, "nodes")
+	if len(selectors) == 0 {
+		selectors = []string{"clients"}
+	}
+	nodes, err := resolveNodes(ctx, actx, selectors)
+	if err != nil {
+		return err
+	}
+
+	actx.State[driver.StateFaultTime] = time.Now()
+	actx.State["mode"] = mode
+	actx.Details["mode"] = mode
+	actx.Details["source"] = net.JoinHostPort(host, portStr)
+
+	// Per node: commands that undo what was applied, run in reverse
+	undo := make(map[string][]string)
+	actx.State["undo"] = undo
+	ips := make(map[string]string)
+	actx.Details["resolved"] = ips
+
+	var faulted []string
+	for _, node := range nodes {
+		client, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			return fmt.Errorf("connecting to %s: %w", node.Name, err)
+		}
+
+		ip, err := resolveOnNode(ctx, client, host)
+		if err == nil {
+			ips[node.Name] = ip
+			err = a.apply(ctx, client, mode, ip, port, args, func(cmd string) {
+				undo[node.Name] = append(undo[node.Name], cmd)
+			})
+		}
+		client.Close()
+
+		// Record the node even on failure so rollback undoes partial work
+		if len(undo[node.Name]) > 0 {
+			faulted = append(faulted, node.Name)
+			actx.State["nodes"] = faulted
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", node.Name, err)
+		}
+	}
+	actx.Details["nodes"] = faulted
+	return nil
+}
+
+// apply runs the fault commands for one node, registering an undo command
+// after each step that succeeds.
+func (a *ArtifactOutageAction) apply(ctx context.Context, client driver.SSHClient, mode, ip string, port int, args map[string]any, onUndo func(string)) error {
+	match := fmt.Sprintf("-p tcp -d %s --dport %d -m comment --comment %s", ip, port, artifactComment)
+
+	switch mode {
+	case "block":
+		rule := "OUTPUT " + match + " -j REJECT --reject-with tcp-reset"
+		if _, err := runSudo(ctx, client, "iptables -I "+rule); err != nil {
+			return fmt.Errorf("adding reject rule: %w", err)
+		}
+		onUndo("iptables -D " + rule)
+
+	case "slow":
+		delay, err := params.Duration(args, "delay", 2*time.Second)
+		if err != nil {
+			return err
+		}
+		rate := params.String(args, "rate", "256kbit")
+
+		dev, err := routeDevice(ctx, client, ip)
+		if err != nil {
+			return err
+		}
+		// Match both directions: requests go to the port, the download
+		// comes back from it (on lo both pass the same device)
+		script := fmt.Sprintf(`tc qdisc add dev %[1]s root handle 1: prio &&
+tc qdisc add dev %[1]s parent 1:3 handle 30: netem delay %[2]dms rate %[3]s &&
+tc filter add dev %[1]s parent 1:0 protocol ip u32 match ip dport %[4]d 0xffff flowid 1:3 &&
+tc filter add dev %[1]s parent 1:0 protocol ip u32 match ip sport %[4]d 0xffff flowid 1:3`, dev, delay.Milliseconds(), rate, port)
+		_, err = runSudo(ctx, client, fmt.Sprintf("sh -c '%s'", script))
+		// The root qdisc may exist even if a later step failed
+		onUndo(fmt.Sprintf("tc qdisc del dev %s root", dev))
+		if err != nil {
+			return fmt.Errorf("adding netem on %s (is a custom root qdisc already set?): %w", dev, err)
+		}
+
+	case "corrupt":
+		listen := params.Int(args, "listen_port", 18888)
+		size, err := parseSize(params.String(args, "size", "64M"))
+		if err != nil {
+			return fmt.Errorf("invalid size: %w", err)
+		}
+
+		encoded := base64.StdEncoding.EncodeToString([]byte(interposerSource))
+		if _, err := runSudo(ctx, client, fmt.Sprintf("sh -c 'echo %s | base64 -d > %s'", encoded, artifactScript)); err != nil {
+			return fmt.Errorf("writing interposer: %w", err)
+		}
+		onUndo("rm -f " + artifactScript)
+
+		if _, err := runSudo(ctx, client, fmt.Sprintf("systemd-run --unit=%s --collect python3 %s %d %d",
+			artifactUnit, artifactScript, listen, size)); err != nil {
+			return fmt.Errorf("starting interposer: %w", err)
+		}
+		onUndo(fmt.Sprintf("sh -c 'systemctl stop %[1]s; systemctl reset-failed %[1]s' 2>/dev/null", artifactUnit))
+
+		rule := fmt.Sprintf("OUTPUT %s -j REDIRECT --to-ports %d", match, listen)
+		if _, err := runSudo(ctx, client, "iptables -t nat -I "+rule); err != nil {
+			return fmt.Errorf("adding redirect rule: %w", err)
+		}
+		onUndo("iptables -t nat -D " + rule)
+	}
+	return nil
+}
+
+// Rollback runs the recorded undo commands on each node, newest first.
+func (a *ArtifactOutageAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	return runUndo(ctx, actx)
+}
+
+// resolveOnNode resolves host to an IPv4 address the way the node sees it.
+func resolveOnNode(ctx context.Context, client driver.SSHClient, host string) (string, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return host, nil
+	}
+	out, err := runSudo(ctx, client, "getent ahostsv4 "+host)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", host, err)
+	}
+	fields := strings.Fields(out)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("resolving %s: no address", host)
+	}
+	return fields[0], nil
+}
+
+// routeDevice returns the network device used to reach ip.
+func routeDevice(ctx context.Context, client driver.SSHClient, ip string) (string, error) {
+	out, err := runSudo(ctx, client, "ip -o route get "+ip)
+	if err != nil {
+		return "", fmt.Errorf("finding route to %s: %w", ip, err)
+	}
+	fields := strings.Fields(out)
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] == "dev" {
+			return fields[i+1], nil
+		}
+	}
+	return "", fmt.Errorf("no device in route to %s: %q", ip, out)
+}
@@ -0,0 +1,42 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+)
+
+// CaptureLeaderAction records the current Nomad leader so a later
+// leader-changed assertion can compare against it.
+type CaptureLeaderAction struct{}
+
+// Name returns the action identifier.
+func (a *CaptureLeaderAction) Name() string {
+	return "capture-leader"
+}
+
+// Description returns a human-readable description.
+func (a *CaptureLeaderAction) Description() string {
+	return "Record the current Nomad leader for a later leader-changed assertion"
+}
+
+// Execute records the leader. The capture time stands in for the fault
+// time when the following fault does not record one.
+func (a *CaptureLeaderAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	leader, err := actx.Driver.GetNomadLeader(ctx, actx.Cluster)
+	if err != nil {
+		return fmt.Errorf("finding leader: %w", err)
+	}
+
+	actx.State[driver.StateLeader] = leader.Name
+	actx.State["captured_at"] = time.Now()
+	actx.Details["leader"] = leader.Name
+	return nil
+}
+
+// Rollback is a no-op; capturing changes nothing.
+func (a *CaptureLeaderAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	return nil
+}
@@ -0,0 +1,242 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// timeSyncUnits are the time synchronisation services stopped during a skew.
+var timeSyncUnits = []string{"chrony", "chronyd", "systemd-timesyncd", "ntp", "ntpsec"}
+
+// clockDriftUnit is the transient systemd unit that applies a slow drift.
+const clockDriftUnit = "chaos-clock-drift"
+
+// ClockSkewAction shifts the system clock on nodes with time sync disabled.
+type ClockSkewAction struct{}
+
+// Name returns the action identifier.
+func (a *ClockSkewAction) Name() string {
+	return "clock-skew"
+}
+
+// Description returns a human-readable description.
+func (a *ClockSkewAction) Description() string {
+	return "Stop time sync and shift node clocks by an offset, as a jump or a slow drift"
+}
+
+// Execute stops time sync on the target nodes and applies the clock offset.
+func (a *ClockSkewAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	offset, err := params.Duration(args, "offset", 0)
+	if err != nil {
+		return err
+	}
+	if offset == 0 {
+		return fmt.Errorf("offset is required (e.g. 30s or -2m)")
+	}
+
+	mode := params.String(args, "mode", "jump")
+	if mode != "jump" && mode != "drift" {
+		return fmt.Errorf("invalid mode %q: must be jump or drift", mode)
+	}
+
+	// Drift applies the offset in one-second increments over this period
+	driftOver, err := params.Duration(args, "duration", time.Minute)
+	if err != nil {
+		return err
+	}
+	if mode == "drift" && driftOver < time.Second {
+		return fmt.Errorf("drift duration must be at least 1s")
+	}
+
+	selectors := params.StringSlice(args, "nodes")
+	if len(selectors) == 0 {
+		selectors = []string{"leader"}
+	}
+	nodes, err := resolveNodes(ctx, actx, selectors)
+	if err != nil {
+		return err
+	}
+
+	actx.State["nodes"] = nodeNames(nodes)
+	actx.State["offset"] = offset
+	actx.State["mode"] = mode
+	actx.Details["offset"] = offset.String()
+	actx.Details["mode"] = mode
+
+	// A drift has barely started when Execute returns, so record its rate
+	// rather than an offset measured right after starting it
+	steps := int(driftOver / time.Second)
+	step := offset / time.Duration(max(steps, 1))
+	if mode == "drift" {
+		actx.Details["drift_duration"] = driftOver.String()
+		actx.Details["drift_rate"] = step.String() + "/s"
+	}
+
+	// offset_after holds a jump as measured right after it; Rollback
+	// replaces it with the offset observed when the fault ends, which is
+	// the only measurement of a drift
+	stoppedUnits := make(map[string][]string)
+	before := make(map[string]string)
+	after := make(map[string]string)
+	actx.State["stopped_units"] = stoppedUnits
+	actx.Details["offset_before"] = before
+	actx.Details["offset_after"] = after
+
+	for _, node := range nodes {
+		client, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			return fmt.Errorf("connecting to %s: %w", node.Name, err)
+		}
+
+		if measured, err := measureClockOffset(ctx, client); err == nil {
+			before[node.Name] = measured.String()
+		}
+
+		units, err := stopTimeSync(ctx, client)
+		stoppedUnits[node.Name] = units
+		if err != nil {
+			client.Close()
+			return fmt.Errorf("stopping time sync on %s: %w", node.Name, err)
+		}
+
+		if mode == "jump" {
+			_, err = runSudo(ctx, client, fmt.Sprintf("sh -c '%s'", shiftClockScript(offset)))
+		} else {
+			script := fmt.Sprintf("i=0; while [ $i -lt %d ]; do %s; i=$((i+1)); sleep 1; done", steps, shiftClockScript(step))
+			_, err = runSudo(ctx, client, fmt.Sprintf("systemd-run --unit=%s --collect sh -c '%s'", clockDriftUnit, script))
+		}
+		if err != nil {
+			client.Close()
+			return fmt.Errorf("shifting clock on %s: %w", node.Name, err)
+		}
+
+		if mode == "jump" {
+			if measured, err := measureClockOffset(ctx, client); err == nil {
+				after[node.Name] = measured.String()
+			}
+		}
+		client.Close()
+	}
+
+	return nil
+}
+
+// Rollback stops any drift, steps the clock back and restarts time sync.
+func (a *ClockSkewAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	nodes, err := nodesFromState(actx, "nodes")
+	if err != nil {
+		return err
+	}
+	stoppedUnits, _ := actx.State["stopped_units"].(map[string][]string)
+
+	observed, _ := actx.Details["offset_after"].(map[string]string)
+	if observed == nil {
+		observed = make(map[string]string)
+		actx.Details["offset_after"] = observed
+	}
+	restored := make(map[string]string)
+	actx.Details["offset_after_rollback"] = restored
+
+	var errs []error
+	for _, node := range nodes {
+		if err := a.restoreNode(ctx, actx.Driver, node, stoppedUnits[node.Name], observed, restored); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", node.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// restoreNode undoes the skew on a single node, recording the offset it
+// had under the fault in observed and the final offset in restored.
+func (a *ClockSkewAction) restoreNode(ctx context.Context, drv driver.Driver, node driver.Node, units []string, observed, restored map[string]string) error {
+	client, err := drv.SSH(ctx, node)
+	if err != nil {
+		return fmt.Errorf("connecting: %w", err)
+	}
+	defer client.Close()
+
+	if err := stopTransientUnit(ctx, client, clockDriftUnit); err != nil {
+		return fmt.Errorf("stopping drift: %w", err)
+	}
+
+	// Step back by whatever offset is currently observed so the node is
+	// close to correct even when no time sync service was running.
+	measured, err := measureClockOffset(ctx, client)
+	if err != nil {
+		return fmt.Errorf("measuring offset: %w", err)
+	}
+	observed[node.Name] = measured.String()
+	if _, err := runSudo(ctx, client, fmt.Sprintf("sh -c '%s'", shiftClockScript(-measured))); err != nil {
+		return fmt.Errorf("stepping clock back: %w", err)
+	}
+
+	for _, unit := range units {
+		if _, err := runSudo(ctx, client, "systemctl start "+unit); err != nil {
+			return err
+		}
+		if unit == "chrony" || unit == "chronyd" {
+			_, _, _, _ = client.RunWithSudo(ctx, "chronyc makestep")
+		}
+	}
+
+	if measured, err := measureClockOffset(ctx, client); err == nil {
+		restored[node.Name] = measured.String()
+	}
+	return nil
+}
+
+// stopTimeSync stops every active time sync unit and returns their names.
+func stopTimeSync(ctx context.Context, client driver.SSHClient) ([]string, error) {
+	var stopped []string
+	for _, unit := range timeSyncUnits {
+		_, _, exitCode, err := client.Run(ctx, "systemctl is-active --quiet "+unit)
+		if err != nil {
+			return stopped, err
+		}
+		if exitCode != 0 {
+			continue
+		}
+		if _, err := runSudo(ctx, client, "systemctl stop "+unit); err != nil {
+			return stopped, err
+		}
+		stopped = append(stopped, unit)
+	}
+	return stopped, nil
+}
+
+// shiftClockScript returns a shell snippet that moves the clock by offset
+// with nanosecond precision, relative to the node's current time.
+func shiftClockScript(offset time.Duration) string {
+	return fmt.Sprintf(`t=$(($(date +%%s%%N) + %d)); date -s "@$((t / 1000000000)).$(printf %%09d $((t %% 1000000000)))" >/dev/null`,
+		offset.Nanoseconds())
+}
+
+// measureClockOffset returns how far the node's clock is ahead of the local
+// clock, compensating for half the SSH round trip.
+func measureClockOffset(ctx context.Context, client driver.SSHClient) (time.Duration, error) {
+	sent := time.Now()
+	stdout, _, exitCode, err := client.Run(ctx, "date +%s%N")
+	rtt := time.Since(sent)
+	if err != nil {
+		return 0, err
+	}
+	if exitCode != 0 {
+		return 0, fmt.Errorf("date failed (exit %d)", exitCode)
+	}
+
+	ns, err := strconv.ParseInt(strings.TrimSpace(stdout), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing remote time %q: %w", stdout, err)
+	}
+
+	remote := time.Unix(0, ns)
+	return remote.Sub(sent.Add(rtt / 2)).Round(time.Millisecond), nil
+}
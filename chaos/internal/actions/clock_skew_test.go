@@ -0,0 +1,54 @@
+package actions
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClockSkewDriftRecordsRateAndOffsets(t *testing.T) {
+	ssh := &fakeSSH{}
+	actx := newFakeContext(ssh)
+
+	err := (&ClockSkewAction{}).Execute(context.Background(), actx, map[string]any{
+		"nodes":    "server-0",
+		"offset":   "30s",
+		"mode":     "drift",
+		"duration": "1m",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := actx.Details["drift_rate"]; got != "500ms/s" {
+		t.Errorf("drift_rate = %v, want 500ms/s", got)
+	}
+	if after := actx.Details["offset_after"].(map[string]string); len(after) != 0 {
+		t.Errorf("drift recorded offset_after %v before it had run", after)
+	}
+
+	var started bool
+	for _, cmd := range ssh.cmds {
+		if strings.HasPrefix(cmd, "systemd-run --unit="+clockDriftUnit) && strings.Contains(cmd, "-lt 60 ]") {
+			started = true
+		}
+	}
+	if !started {
+		t.Errorf("commands %q do not start a 60-step drift unit", ssh.cmds)
+	}
+
+	// Rollback measures the offset the drift reached before stepping back
+	remote := time.Now().Add(20 * time.Second).UnixNano()
+	ssh.outputs = map[string]string{"date ": strconv.FormatInt(remote, 10)}
+	if err := (&ClockSkewAction{}).Rollback(context.Background(), actx); err != nil {
+		t.Fatal(err)
+	}
+	got, err := time.ParseDuration(actx.Details["offset_after"].(map[string]string)["server-0"])
+	if err != nil {
+		t.Fatalf("offset_after: %v", err)
+	}
+	if got < 19*time.Second || got > 20*time.Second {
+		t.Errorf("offset_after = %s, want about 20s", got)
+	}
+}
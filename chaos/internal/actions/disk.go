@@ -0,0 +1,448 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// defaultDataDir matches nomad_data_dir in the ansible nomad role defaults.
+const defaultDataDir = "/opt/nomad/data"
+
+// diskFillFile is the name of the ballast file created by disk-fill.
+const diskFillFile = ".chaos-disk-fill"
+
+// DiskFillAction fills the filesystem holding a path to a target percentage.
+type DiskFillAction struct{}
+
+// Name returns the action identifier.
+func (a *DiskFillAction) Name() string {
+	return "disk-fill"
+}
+
+// Description returns a human-readable description.
+func (a *DiskFillAction) Description() string {
+	return "Fill the filesystem holding a path to a target usage percentage with fallocate"
+}
+
+// Execute allocates a ballast file large enough to reach the target usage.
+func (a *DiskFillAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	dir := params.String(args, "path", defaultDataDir)
+	percent := params.Int(args, "percent", 95)
+	if percent < 1 || percent > 100 {
+		return fmt.Errorf("invalid percent %d: must be between 1 and 100", percent)
+	}
+
+	nodes, err := diskTargets(ctx, actx, args)
+	if err != nil {
+		return err
+	}
+
+	file := path.Join(dir, diskFillFile)
+	actx.State["nodes"] = nodeNames(nodes)
+	actx.State["file"] = file
+	actx.Details["path"] = dir
+	actx.Details["percent"] = percent
+
+	before := make(map[string]diskUsage)
+	after := make(map[string]diskUsage)
+	actx.Details["df_before"] = before
+	actx.Details["df_after"] = after
+
+	for _, node := range nodes {
+		client, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			return fmt.Errorf("connecting to %s: %w", node.Name, err)
+		}
+
+		usage, err := readDiskUsage(ctx, client, dir)
+		if err != nil {
+			client.Close()
+			return fmt.Errorf("reading usage on %s: %w", node.Name, err)
+		}
+		before[node.Name] = usage
+
+		// Leave root-reserved blocks alone; size is what df reports
+		fill := usage.Size*int64(percent)/100 - usage.Used
+		if fill > usage.Avail {
+			fill = usage.Avail
+		}
+		if fill <= 0 {
+			client.Close()
+			return fmt.Errorf("%s on %s is already %s full", usage.Mount, node.Name, usage.UsePercent)
+		}
+
+		if _, err := runSudo(ctx, client, fmt.Sprintf("fallocate -l %d %s", fill, file)); err != nil {
+			client.Close()
+			return fmt.Errorf("filling %s on %s: %w", usage.Mount, node.Name, err)
+		}
+
+		if usage, err := readDiskUsage(ctx, client, dir); err == nil {
+			after[node.Name] = usage
+		}
+		client.Close()
+	}
+
+	return nil
+}
+
+// Rollback deletes the ballast file.
+func (a *DiskFillAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	nodes, err := nodesFromState(actx, "nodes")
+	if err != nil {
+		return err
+	}
+	file, _ := actx.State["file"].(string)
+
+	return rollbackDisk(ctx, actx, nodes, path.Dir(file), func(_ driver.Node, client driver.SSHClient) error {
+		_, err := runSudo(ctx, client, "rm -f "+file)
+		return err
+	})
+}
+
+// IOThrottleAction limits a unit's I/O on the device backing a path.
+type IOThrottleAction struct{}
+
+// Name returns the action identifier.
+func (a *IOThrottleAction) Name() string {
+	return "io-throttle"
+}
+
+// Description returns a human-readable description.
+func (a *IOThrottleAction) Description() string {
+	return "Throttle a systemd unit's I/O on the data device with IO*Max properties"
+}
+
+// ioLimits maps the action's args to systemd resource-control properties.
+var ioLimits = []struct{ arg, property string }{
+	{"read_bps", "IOReadBandwidthMax"},
+	{"write_bps", "IOWriteBandwidthMax"},
+	{"read_iops", "IOReadIOPSMax"},
+	{"write_iops", "IOWriteIOPSMax"},
+}
+
+// Execute sets runtime IO*Max properties on the unit for the filesystem
+// holding the path. systemd resolves the path to its backing disk and
+// writes the cgroup's io.max itself, so the limit survives the unit's
+// cgroup being recreated and is dropped at the next boot.
+func (a *IOThrottleAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	dir := params.String(args, "path", defaultDataDir)
+	unit := params.String(args, "service", "nomad")
+
+	var properties []string
+	for _, l := range ioLimits {
+		value := params.String(args, l.arg, "")
+		if value == "" {
+			continue
+		}
+		n, err := parseSize(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", l.arg, err)
+		}
+		properties = append(properties, fmt.Sprintf("%s=%s %d", l.property, dir, n))
+	}
+	if len(properties) == 0 {
+		return fmt.Errorf("at least one of read_bps, write_bps, read_iops or write_iops is required")
+	}
+
+	nodes, err := diskTargets(ctx, actx, args)
+	if err != nil {
+		return err
+	}
+
+	actx.State["path"] = dir
+	actx.State["service"] = unit
+	actx.Details["path"] = dir
+	actx.Details["service"] = unit
+	actx.Details["limits"] = properties
+
+	// Per node: the unit's IO*Max assignments before the fault
+	original := make(map[string][]string)
+	actx.State["original"] = original
+
+	before := make(map[string]diskUsage)
+	actx.Details["df_before"] = before
+
+	// Only nodes that were actually throttled are recorded for rollback
+	throttled := []string{}
+	actx.State["nodes"] = throttled
+	for _, node := range nodes {
+		client, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			return fmt.Errorf("connecting to %s: %w", node.Name, err)
+		}
+
+		if usage, err := readDiskUsage(ctx, client, dir); err == nil {
+			before[node.Name] = usage
+		}
+
+		current, err := readIOLimits(ctx, client, unit)
+		if err != nil {
+			client.Close()
+			return fmt.Errorf("reading I/O limits of %s on %s: %w", unit, node.Name, err)
+		}
+
+		if _, err := runSudo(ctx, client, setPropertyCmd(unit, properties)); err != nil {
+			client.Close()
+			return fmt.Errorf("throttling %s on %s: %w", unit, node.Name, err)
+		}
+		original[node.Name] = current
+		throttled = append(throttled, node.Name)
+		actx.State["nodes"] = throttled
+		client.Close()
+	}
+
+	return nil
+}
+
+// Rollback clears the unit's IO*Max properties and reapplies the ones it
+// had before.
+func (a *IOThrottleAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	if names, _ := actx.State["nodes"].([]string); len(names) == 0 {
+		return nil
+	}
+	nodes, err := nodesFromState(actx, "nodes")
+	if err != nil {
+		return err
+	}
+	dir, _ := actx.State["path"].(string)
+	unit, _ := actx.State["service"].(string)
+	original, _ := actx.State["original"].(map[string][]string)
+
+	return rollbackDisk(ctx, actx, nodes, dir, func(node driver.Node, client driver.SSHClient) error {
+		var properties []string
+		for _, l := range ioLimits {
+			properties = append(properties, l.property+"=")
+		}
+		properties = append(properties, original[node.Name]...)
+		_, err := runSudo(ctx, client, setPropertyCmd(unit, properties))
+		return err
+	})
+}
+
+// readIOLimits returns the unit's non-empty IO*Max assignments as
+// Property=value lines, as systemctl show prints them.
+func readIOLimits(ctx context.Context, client driver.SSHClient, unit string) ([]string, error) {
+	cmd := "systemctl show " + unit
+	for _, l := range ioLimits {
+		cmd += " -p " + l.property
+	}
+	out, err := runSudo(ctx, client, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var assignments []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if name, value, ok := strings.Cut(line, "="); ok && name != "" && value != "" {
+			assignments = append(assignments, line)
+		}
+	}
+	return assignments, nil
+}
+
+// setPropertyCmd builds a runtime systemctl set-property command. Each
+// assignment is quoted since values hold a path and a limit.
+func setPropertyCmd(unit string, properties []string) string {
+	cmd := "systemctl set-property --runtime " + unit
+	for _, p := range properties {
+		cmd += " '" + p + "'"
+	}
+	return cmd
+}
+
+// ReadonlyRemountAction remounts the filesystem holding a path read-only.
+type ReadonlyRemountAction struct{}
+
+// Name returns the action identifier.
+func (a *ReadonlyRemountAction) Name() string {
+	return "readonly-remount"
+}
+
+// Description returns a human-readable description.
+func (a *ReadonlyRemountAction) Description() string {
+	return "Remount the filesystem holding a path read-only (fails if the mount is busy)"
+}
+
+// Execute remounts the filesystem read-only.
+func (a *ReadonlyRemountAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	dir := params.String(args, "path", defaultDataDir)
+
+	nodes, err := diskTargets(ctx, actx, args)
+	if err != nil {
+		return err
+	}
+
+	actx.State["path"] = dir
+	actx.Details["path"] = dir
+	mounts := make(map[string]string)
+	before := make(map[string]diskUsage)
+	after := make(map[string]diskUsage)
+	actx.State["mounts"] = mounts
+	actx.Details["df_before"] = before
+	actx.Details["df_after"] = after
+
+	// Only nodes that were actually remounted are recorded for rollback
+	remounted := []string{}
+	actx.State["nodes"] = remounted
+	for _, node := range nodes {
+		client, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			return fmt.Errorf("connecting to %s: %w", node.Name, err)
+		}
+
+		usage, err := readDiskUsage(ctx, client, dir)
+		if err != nil {
+			client.Close()
+			return fmt.Errorf("reading usage on %s: %w", node.Name, err)
+		}
+		before[node.Name] = usage
+
+		opts, err := runSudo(ctx, client, "findmnt -n -o OPTIONS --target "+usage.Mount)
+		if err != nil {
+			client.Close()
+			return fmt.Errorf("reading mount options on %s: %w", node.Name, err)
+		}
+		if strings.HasPrefix(strings.TrimSpace(opts), "ro") {
+			client.Close()
+			return fmt.Errorf("%s on %s is already read-only", usage.Mount, node.Name)
+		}
+
+		if _, err := runSudo(ctx, client, "mount -o remount,ro "+usage.Mount); err != nil {
+			client.Close()
+			return fmt.Errorf("remounting %s read-only on %s (busy mounts such as / cannot be remounted): %w",
+				usage.Mount, node.Name, err)
+		}
+
+		mounts[node.Name] = usage.Mount
+		remounted = append(remounted, node.Name)
+		actx.State["nodes"] = remounted
+
+		if usage, err := readDiskUsage(ctx, client, dir); err == nil {
+			after[node.Name] = usage
+		}
+		client.Close()
+	}
+
+	return nil
+}
+
+// Rollback remounts the filesystem read-write. Nothing is done when no
+// node was remounted.
+func (a *ReadonlyRemountAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	if names, _ := actx.State["nodes"].([]string); len(names) == 0 {
+		return nil
+	}
+	nodes, err := nodesFromState(actx, "nodes")
+	if err != nil {
+		return err
+	}
+	dir, _ := actx.State["path"].(string)
+	mounts, _ := actx.State["mounts"].(map[string]string)
+
+	return rollbackDisk(ctx, actx, nodes, dir, func(node driver.Node, client driver.SSHClient) error {
+		_, err := runSudo(ctx, client, "mount -o remount,rw "+mounts[node.Name])
+		return err
+	})
+}
+
+// diskUsage is a parsed df line, recorded in action details.
+type diskUsage struct {
+	Source     string `json:"source"`
+	FSType     string `json:"fstype"`
+	Size       int64  `json:"size_bytes"`
+	Used       int64  `json:"used_bytes"`
+	Avail      int64  `json:"avail_bytes"`
+	UsePercent string `json:"use_percent"`
+	Mount      string `json:"mount"`
+}
+
+// readDiskUsage runs df for the filesystem holding dir.
+func readDiskUsage(ctx context.Context, client driver.SSHClient, dir string) (diskUsage, error) {
+	out, err := runSudo(ctx, client, "df -B1 --output=source,fstype,size,used,avail,pcent,target "+dir)
+	if err != nil {
+		return diskUsage{}, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 2 {
+		return diskUsage{}, fmt.Errorf("unexpected df output: %q", out)
+	}
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 7 {
+		return diskUsage{}, fmt.Errorf("unexpected df output: %q", out)
+	}
+
+	usage := diskUsage{
+		Source:     fields[0],
+		FSType:     fields[1],
+		UsePercent: fields[5],
+		Mount:      fields[6],
+	}
+	for i, dst := range []*int64{&usage.Size, &usage.Used, &usage.Avail} {
+		n, err := strconv.ParseInt(fields[2+i], 10, 64)
+		if err != nil {
+			return diskUsage{}, fmt.Errorf("parsing df field %q: %w", fields[2+i], err)
+		}
+		*dst = n
+	}
+	return usage, nil
+}
+
+// diskTargets resolves the nodes argument for disk actions (default: leader).
+func diskTargets(ctx context.Context, actx *driver.ActionContext, args map[string]any) ([]driver.Node, error) {
+	selectors := params.StringSlice(args, "nodes")
+	if len(selectors) == 0 {
+		selectors = []string{"leader"}
+	}
+	return resolveNodes(ctx, actx, selectors)
+}
+
+// rollbackDisk runs undo on each node and records df afterwards.
+func rollbackDisk(ctx context.Context, actx *driver.ActionContext, nodes []driver.Node, dir string, undo func(driver.Node, driver.SSHClient) error) error {
+	restored := make(map[string]diskUsage)
+	actx.Details["df_after_rollback"] = restored
+
+	var errs []error
+	for _, node := range nodes {
+		client, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("connecting to %s: %w", node.Name, err))
+			continue
+		}
+		if err := undo(node, client); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", node.Name, err))
+		} else if usage, err := readDiskUsage(ctx, client, dir); err == nil {
+			restored[node.Name] = usage
+		}
+		client.Close()
+	}
+
+	return errors.Join(errs...)
+}
+
+// parseSize parses a plain number or one with a K, M or G (1024-based) suffix.
+func parseSize(s string) (int64, error) {
+	s = strings.TrimSpace(strings.ToUpper(s))
+	mult := int64(1)
+	switch {
+	case strings.HasSuffix(s, "K"):
+		mult, s = 1<<10, strings.TrimSuffix(s, "K")
+	case strings.HasSuffix(s, "M"):
+		mult, s = 1<<20, strings.TrimSuffix(s, "M")
+	case strings.HasSuffix(s, "G"):
+		mult, s = 1<<30, strings.TrimSuffix(s, "G")
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * mult, nil
+}
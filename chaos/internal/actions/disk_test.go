@@ -0,0 +1,149 @@
+package actions
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+)
+
+// fakeSSH records commands and answers them from canned outputs keyed by
+// command prefix.
+type fakeSSH struct {
+	outputs map[string]string
+	fail    map[string]bool
+	cmds    []string
+}
+
+func (f *fakeSSH) Run(ctx context.Context, cmd string) (string, string, int, error) {
+	return f.RunWithSudo(ctx, cmd)
+}
+
+func (f *fakeSSH) RunWithSudo(_ context.Context, cmd string) (string, string, int, error) {
+	f.cmds = append(f.cmds, cmd)
+	for prefix := range f.fail {
+		if strings.HasPrefix(cmd, prefix) {
+			return "", "failed", 1, nil
+		}
+	}
+	for prefix, out := range f.outputs {
+		if strings.HasPrefix(cmd, prefix) {
+			return out, "", 0, nil
+		}
+	}
+	return "", "", 0, nil
+}
+
+func (f *fakeSSH) Stream(context.Context, string, io.Writer, io.Writer) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeSSH) Close() error {
+	return nil
+}
+
+// fakeDriver hands out one shared fakeSSH, or a node's own from nodes, and
+// a Nomad client for the leader.
+// Other Driver methods are not used by the actions under test and panic if
+// called.
+type fakeDriver struct {
+	driver.Driver
+	ssh   *fakeSSH
+	nodes map[string]*fakeSSH
+	nomad *nomad.Client // served for the first server, the leader
+}
+
+func (d *fakeDriver) GetNomadLeader(_ context.Context, cluster *driver.Cluster) (*driver.Node, error) {
+	return &cluster.Servers[0], nil
+}
+
+func (d *fakeDriver) NomadClient(driver.Node) (*nomad.Client, error) {
+	return d.nomad, nil
+}
+
+func (d *fakeDriver) SSH(_ context.Context, node driver.Node) (driver.SSHClient, error) {
+	if ssh, ok := d.nodes[node.Name]; ok {
+		return ssh, nil
+	}
+	return d.ssh, nil
+}
+
+func newFakeContext(ssh *fakeSSH) *driver.ActionContext {
+	cluster := &driver.Cluster{
+		Servers: []driver.Node{
+			{Name: "server-0", Role: driver.RoleServer},
+			{Name: "server-1", Role: driver.RoleServer, Index: 1},
+		},
+	}
+	return driver.NewActionContext(&fakeDriver{ssh: ssh}, cluster)
+}
+
+const dfOutput = `Filesystem Type Size Used Avail Use% Mounted on
+/dev/nvme0n1p1 ext4 100 40 60 40% /
+`
+
+func TestIOThrottleSetsRuntimeProperties(t *testing.T) {
+	ssh := &fakeSSH{outputs: map[string]string{
+		"df ":            dfOutput,
+		"systemctl show": "IOReadBandwidthMax=\nIOWriteBandwidthMax=/dev/nvme0n1 2097152\nIOReadIOPSMax=\nIOWriteIOPSMax=\n",
+	}}
+	actx := newFakeContext(ssh)
+	a := &IOThrottleAction{}
+
+	err := a.Execute(context.Background(), actx, map[string]any{
+		"nodes":     "server-0",
+		"write_bps": "1M",
+		"read_iops": "100",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "systemctl set-property --runtime nomad 'IOWriteBandwidthMax=/opt/nomad/data 1048576' 'IOReadIOPSMax=/opt/nomad/data 100'"
+	if !slices.Contains(ssh.cmds, want) {
+		t.Errorf("commands %q do not contain %q", ssh.cmds, want)
+	}
+
+	ssh.cmds = nil
+	if err := a.Rollback(context.Background(), actx); err != nil {
+		t.Fatal(err)
+	}
+	want = "systemctl set-property --runtime nomad 'IOReadBandwidthMax=' 'IOWriteBandwidthMax=' 'IOReadIOPSMax=' 'IOWriteIOPSMax=' 'IOWriteBandwidthMax=/dev/nvme0n1 2097152'"
+	if !slices.Contains(ssh.cmds, want) {
+		t.Errorf("rollback commands %q do not contain %q", ssh.cmds, want)
+	}
+}
+
+func TestIOThrottleRequiresLimit(t *testing.T) {
+	actx := newFakeContext(&fakeSSH{})
+	if err := (&IOThrottleAction{}).Execute(context.Background(), actx, map[string]any{"nodes": "server-0"}); err == nil {
+		t.Error("Execute without limits succeeded")
+	}
+}
+
+func TestReadonlyRemountRollbackAfterFirstNodeFails(t *testing.T) {
+	ssh := &fakeSSH{
+		outputs: map[string]string{
+			"df ":     dfOutput,
+			"findmnt": "rw,relatime\n",
+		},
+		fail: map[string]bool{"mount -o remount,ro": true},
+	}
+	actx := newFakeContext(ssh)
+	a := &ReadonlyRemountAction{}
+
+	if err := a.Execute(context.Background(), actx, map[string]any{"nodes": "server-0,server-1"}); err == nil {
+		t.Fatal("Execute succeeded although the remount failed")
+	}
+
+	ssh.cmds = nil
+	if err := a.Rollback(context.Background(), actx); err != nil {
+		t.Errorf("Rollback after no node was remounted: %v", err)
+	}
+	if len(ssh.cmds) != 0 {
+		t.Errorf("Rollback ran %q, want nothing", ssh.cmds)
+	}
+}
@@ -0,0 +1,295 @@
+package actions
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+const (
+	// dnsUnit runs the faulting resolver on the node.
+	dnsUnit = "chaos-dns"
+	// dnsScript is where the resolver is written on the node.
+	dnsScript = "/run/chaos-dns.py"
+	// dnsListen is the loopback address the resolver answers on. It is
+	// outside the addresses systemd-resolved binds (127.0.0.53 and .54).
+	dnsListen = "127.0.0.153"
+	// dnsBackup holds the original /etc/resolv.conf (or symlink) during the fault.
+	dnsBackup = "/run/chaos-dns.resolv.conf"
+	// dnsComment tags the iptables rules added by dns-fault.
+	dnsComment = "chaos-dns"
+	// resolvConfState identifies /etc/resolv.conf exactly: type, mode,
+	// owner, symlink target and content.
+	resolvConfState = "sh -c 'stat -c %F:%a:%U:%G:%N /etc/resolv.conf && sha256sum < /etc/resolv.conf'"
+)
+
+// resolverSource answers UDP queries for the listed names (and their
+// subdomains, or every name when none are listed) with an error rcode
+// and/or after a delay, and forwards everything else to the original
+// nameserver. Arguments: listen address, upstream, rcode, delay seconds,
+// comma-separated names.
+const resolverSource = `import socket, sys, threading, time
+
+LISTEN, UPSTREAM, RCODE, DELAY = sys.argv[1], sys.argv[2], int(sys.argv[3]), float(sys.argv[4])
+NAMES = [n.strip(".").lower() for n in sys.argv[5].split(",") if n.strip(".")]
+
+def question(msg):
+    labels, i = [], 12
+    while i < len(msg) and msg[i]:
+        labels.append(msg[i + 1:i + 1 + msg[i]].decode("ascii", "replace"))
+        i += 1 + msg[i]
+    return ".".join(labels).lower(), i + 5
+
+def handle(sock, msg, addr):
+    name, end = question(msg)
+    if not NAMES or any(name == n or name.endswith("." + n) for n in NAMES):
+        if DELAY:
+            time.sleep(DELAY)
+        if RCODE:
+            flags = bytes([0x80 | (msg[2] & 0x79), 0x80 | RCODE])
+            sock.sendto(msg[:2] + flags + msg[4:6] + bytes(6) + msg[12:end], addr)
+            return
+    up = socket.socket(socket.AF_INET, socket.SOCK_DGRAM)
+    up.settimeout(5)
+    try:
+        up.sendto(msg, (UPSTREAM, 53))
+        sock.sendto(up.recv(65535), addr)
+    except OSError:
+        pass
+    finally:
+        up.close()
+
+sock = socket.socket(socket.AF_INET, socket.SOCK_DGRAM)
+sock.bind((LISTEN, 53))
+while True:
+    msg, addr = sock.recvfrom(65535)
+    if len(msg) > 12:
+        threading.Thread(target=handle, args=(sock, msg, addr), daemon=True).start()
+`
+
+// DNSFaultAction breaks name resolution on nodes, either at the DNS port or
+// through a temporary resolver that /etc/resolv.conf points at.
+type DNSFaultAction struct{}
+
+// Name returns the action identifier.
+func (a *DNSFaultAction) Name() string {
+	return "dns-fault"
+}
+
+// Description returns a human-readable description.
+func (a *DNSFaultAction) Description() string {
+	return "Block DNS ports, answer SERVFAIL/NXDOMAIN for names, or delay lookups on nodes"
+}
+
+// Execute applies the fault on each node:
+//   - block: drop outgoing UDP and TCP to the port (8600 for Consul DNS,
+//     53 for every resolver)
+//   - servfail, nxdomain: answer the names with that rcode
+//   - delay: delay answers for the names, or all names when none are given
+//
+// The resolver modes point /etc/resolv.conf at a local resolver that
+// forwards other names to the original nameserver. systemd-resolved is not
+// reconfigured; its cache is flushed so cached answers do not hide the fault.
+func (a *DNSFaultAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	mode := params.String(args, "mode", "block")
+	var rcode int
+	switch mode {
+	case "block", "delay":
+	case "servfail":
+		rcode = 2
+	case "nxdomain":
+		rcode = 3
+	default:
+		return fmt.Errorf("invalid mode %q: must be block, servfail, nxdomain or delay", mode)
+	}
+
+	names := params.StringSlice(args, "names")
+	if rcode != 0 && len(names) == 0 {
+		return fmt.Errorf("mode %s requires names", mode)
+	}
+	port := params.Int(args, "port", 8600)
+	delay, err := params.Duration(args, "delay", 2*time.Second)
+	if err != nil {
+		return err
+	}
+	if mode != "delay" {
+		delay = 0
+	}
+	duration, err := params.Duration(args, "duration", 0)
+	if err != nil {
+		return err
+	}
+
+	selectors := params.StringSlice(args, "nodes")
+	if len(selectors) == 0 {
+		selectors = []string{"all"}
+	}
+	nodes, err := resolveNodes(ctx, actx, selectors)
+	if err != nil {
+		return err
+	}
+
+	actx.State[driver.StateFaultTime] = time.Now()
+	actx.Details["mode"] = mode
+	if mode == "block" {
+		actx.Details["port"] = port
+	} else {
+		actx.Details["names"] = names
+	}
+	if delay > 0 {
+		actx.Details["delay"] = delay.String()
+	}
+
+	undo := make(map[string][]string)
+	actx.State["undo"] = undo
+	resolvConf := make(map[string]string)
+	actx.State["resolv_conf"] = resolvConf
+
+	var faulted []string
+	for _, node := range nodes {
+		client, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			return fmt.Errorf("connecting to %s: %w", node.Name, err)
+		}
+
+		onUndo := func(cmd string) {
+			undo[node.Name] = append(undo[node.Name], cmd)
+		}
+		if mode == "block" {
+			err = a.block(ctx, client, port, onUndo)
+		} else {
+			var before string
+			before, err = runSudo(ctx, client, resolvConfState)
+			if err == nil {
+				resolvConf[node.Name] = before
+				err = a.override(ctx, client, rcode, delay, names, onUndo)
+			}
+		}
+		if err == nil {
+			_, _, _, err = client.RunWithSudo(ctx, "resolvectl flush-caches")
+		}
+		client.Close()
+
+		// Record the node even on failure so rollback undoes partial work
+		if len(undo[node.Name]) > 0 {
+			faulted = append(faulted, node.Name)
+			actx.State["nodes"] = faulted
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", node.Name, err)
+		}
+	}
+	actx.Details["nodes"] = faulted
+	return holdOutage(ctx, actx, duration, func() error { return a.Rollback(ctx, actx) })
+}
+
+// block drops DNS traffic to the port.
+func (a *DNSFaultAction) block(ctx context.Context, client driver.SSHClient, port int, onUndo func(string)) error {
+	for _, proto := range []string{"udp", "tcp"} {
+		rule := fmt.Sprintf("OUTPUT -p %s --dport %d -m comment --comment %s -j DROP", proto, port, dnsComment)
+		if _, err := runSudo(ctx, client, "iptables -I "+rule); err != nil {
+			return fmt.Errorf("adding %s drop rule: %w", proto, err)
+		}
+		onUndo("iptables -D " + rule)
+	}
+	return nil
+}
+
+// override starts the faulting resolver and points /etc/resolv.conf at it,
+// keeping the original search and options lines.
+func (a *DNSFaultAction) override(ctx context.Context, client driver.SSHClient, rcode int, delay time.Duration, names []string, onUndo func(string)) error {
+	out, err := runSudo(ctx, client, "cat /etc/resolv.conf")
+	if err != nil {
+		return fmt.Errorf("reading resolv.conf: %w", err)
+	}
+	var upstream string
+	var kept []string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			// The resolver forwards over IPv4 only
+			if upstream == "" && !strings.Contains(fields[1], ":") {
+				upstream = fields[1]
+			}
+			continue
+		}
+		if len(fields) > 0 && (fields[0] == "search" || fields[0] == "domain" || fields[0] == "options") {
+			kept = append(kept, line)
+		}
+	}
+
+	if upstream == "" {
+		upstream = "127.0.0.53"
+	}
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(resolverSource))
+	if _, err := runSudo(ctx, client, fmt.Sprintf("sh -c 'echo %s | base64 -d > %s'", encoded, dnsScript)); err != nil {
+		return fmt.Errorf("writing resolver: %w", err)
+	}
+	onUndo("rm -f " + dnsScript)
+
+	if _, err := runSudo(ctx, client, fmt.Sprintf("systemd-run --unit=%s --collect python3 %s %s %s %d %s %q",
+		dnsUnit, dnsScript, dnsListen, upstream, rcode, strconv.FormatFloat(delay.Seconds(), 'f', -1, 64), strings.Join(names, ","))); err != nil {
+		return fmt.Errorf("starting resolver: %w", err)
+	}
+	onUndo(fmt.Sprintf("sh -c 'systemctl stop %[1]s; systemctl reset-failed %[1]s' 2>/dev/null", dnsUnit))
+
+	// cp -a keeps a symlink to resolved's stub file as a symlink
+	if _, err := runSudo(ctx, client, fmt.Sprintf("cp -a /etc/resolv.conf %s", dnsBackup)); err != nil {
+		return fmt.Errorf("backing up resolv.conf: %w", err)
+	}
+	onUndo(fmt.Sprintf("sh -c 'rm -f /etc/resolv.conf && cp -a %[1]s /etc/resolv.conf && rm -f %[1]s'", dnsBackup))
+
+	content := strings.Join(append([]string{"nameserver " + dnsListen}, kept...), "\n") + "\n"
+	encoded = base64.StdEncoding.EncodeToString([]byte(content))
+	if _, err := runSudo(ctx, client, fmt.Sprintf("sh -c 'rm -f /etc/resolv.conf && echo %s | base64 -d > /etc/resolv.conf'", encoded)); err != nil {
+		return fmt.Errorf("writing resolv.conf: %w", err)
+	}
+	return nil
+}
+
+// Rollback removes the rules or restores /etc/resolv.conf, flushes the
+// resolved cache, and checks resolv.conf is identical to before the fault.
+func (a *DNSFaultAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	nodes, err := nodesFromState(actx, "nodes")
+	if err != nil {
+		return err
+	}
+	resolvConf, _ := actx.State["resolv_conf"].(map[string]string)
+
+	// A node that fails to restore must not keep the others faulted
+	var errs []error
+	if err := runUndo(ctx, actx); err != nil {
+		errs = append(errs, err)
+	}
+	for _, node := range nodes {
+		client, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("connecting to %s: %w", node.Name, err))
+			continue
+		}
+		client.RunWithSudo(ctx, "resolvectl flush-caches")
+
+		before, ok := resolvConf[node.Name]
+		if !ok {
+			client.Close()
+			continue
+		}
+		after, err := runSudo(ctx, client, resolvConfState)
+		client.Close()
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", node.Name, err))
+		case after != before:
+			errs = append(errs, fmt.Errorf("%s: resolv.conf differs after restore: was %q, now %q", node.Name, before, after))
+		}
+	}
+	return errors.Join(errs...)
+}
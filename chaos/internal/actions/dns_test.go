@@ -0,0 +1,50 @@
+package actions
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDNSFaultRollbackContinuesPastFailedNode(t *testing.T) {
+	ssh := &fakeSSH{
+		outputs: map[string]string{"sh -c 'stat": "restored"},
+		fail:    map[string]bool{"undo-0": true},
+	}
+	actx := newFakeContext(ssh)
+	actx.State["nodes"] = []string{"server-0", "server-1"}
+	actx.State["undo"] = map[string][]string{
+		"server-0": {"undo-0"},
+		"server-1": {"undo-1"},
+	}
+	actx.State["resolv_conf"] = map[string]string{
+		"server-0": "original",
+		"server-1": "restored",
+	}
+
+	err := (&DNSFaultAction{}).Rollback(context.Background(), actx)
+	if err == nil {
+		t.Fatal("Rollback succeeded although server-0 failed to restore")
+	}
+	for _, want := range []string{"server-0: undo-0", "server-0: resolv.conf differs"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "server-1") {
+		t.Errorf("error %q blames server-1", err)
+	}
+	if !slices.Contains(ssh.cmds, "undo-1") {
+		t.Errorf("commands %q do not restore server-1", ssh.cmds)
+	}
+	flushes := 0
+	for _, cmd := range ssh.cmds {
+		if cmd == "resolvectl flush-caches" {
+			flushes++
+		}
+	}
+	if flushes != 2 {
+		t.Errorf("flushed caches %d times, want once per node", flushes)
+	}
+}
@@ -0,0 +1,152 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+const (
+	// dockerSocket is the socket the Nomad docker driver connects to.
+	dockerSocket = "/run/docker.sock"
+	// dockerRestoreUnit is the transient timer that ends an outage with a
+	// duration.
+	dockerRestoreUnit = "chaos-docker-restore"
+)
+
+// DockerOutageAction stops or kills dockerd or containerd on clients, or
+// hides the Docker socket from the docker driver.
+type DockerOutageAction struct{}
+
+// Name returns the action identifier.
+func (a *DockerOutageAction) Name() string {
+	return "docker-outage"
+}
+
+// Description returns a human-readable description.
+func (a *DockerOutageAction) Description() string {
+	return "Stop, restart or kill dockerd/containerd on clients, or block the Docker socket"
+}
+
+// Execute records the allocations with running containers, then applies
+// the fault. block-socket moves the socket aside, so dockerd keeps running
+// but the driver cannot reach it. Execute returns once the fault is in
+// place; with a duration a transient timer on each node restores it, so
+// later steps can watch the driver go unhealthy and then recover.
+func (a *DockerOutageAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	mode := params.String(args, "mode", "stop")
+	if mode != "stop" && mode != "restart" && mode != "kill" && mode != "block-socket" {
+		return fmt.Errorf("invalid mode %q: must be stop, restart, kill or block-socket", mode)
+	}
+	duration, err := params.Duration(args, "duration", 0)
+	if err != nil {
+		return err
+	}
+	services := params.StringSlice(args, "services")
+	if len(services) == 0 {
+		services = []string{"docker"}
+	}
+
+	selectors := params.StringSlice(args, "nodes")
+	if len(selectors) == 0 {
+		selectors = []string{"clients"}
+	}
+	nodes, err := resolveNodes(ctx, actx, selectors)
+	if err != nil {
+		return err
+	}
+
+	var allocIDs []string
+	containers := make(map[string]int)
+	for _, node := range nodes {
+		out, err := runOnNode(ctx, actx.Driver, node,
+			fmt.Sprintf(`docker ps --filter label=%[1]s --format "{{.Label \"%[1]s\"}}"`, nomad.DockerAllocIDLabel))
+		if err != nil {
+			return fmt.Errorf("listing containers on %s: %w", node.Name, err)
+		}
+		ids := strings.Fields(out)
+		containers[node.Name] = len(ids)
+		allocIDs = append(allocIDs, ids...)
+	}
+	actx.State[driver.StateAllocIDs] = allocIDs
+	actx.State[driver.StateFaultTime] = time.Now()
+	actx.State["mode"] = mode
+	actx.Details["containers_before"] = containers
+
+	if mode != "block-socket" {
+		if err := faultServices(ctx, actx, nodes, mode, services); err != nil {
+			return err
+		}
+	} else {
+		actx.Details["mode"] = mode
+		var blocked []string
+		for _, node := range nodes {
+			if _, err := runOnNode(ctx, actx.Driver, node, fmt.Sprintf("mv %[1]s %[1]s.chaos", dockerSocket)); err != nil {
+				return fmt.Errorf("moving docker socket on %s: %w", node.Name, err)
+			}
+			blocked = append(blocked, node.Name)
+			actx.State["nodes"] = blocked
+		}
+		actx.Details["nodes"] = blocked
+	}
+
+	if duration <= 0 {
+		return nil
+	}
+	units, _ := actx.State["units"].(map[string][]string)
+	for _, node := range nodes {
+		restore := restoreSocketCmd
+		if mode != "block-socket" {
+			start := services
+			if u := units[node.Name]; len(u) > 0 {
+				start = u
+			}
+			restore = "systemctl start " + strings.Join(start, " ")
+		}
+		// Clear a timer left by an earlier run so systemd-run can reuse the name
+		runOnNode(ctx, actx.Driver, node, clearRestoreCmd)
+		cmd := fmt.Sprintf("systemd-run --unit=%s --collect --on-active=%d sh -c '%s'", dockerRestoreUnit, int(duration/time.Second), restore)
+		if _, err := runOnNode(ctx, actx.Driver, node, cmd); err != nil {
+			return fmt.Errorf("scheduling restore on %s: %w", node.Name, err)
+		}
+	}
+	actx.Details["restore_after"] = duration.String()
+	return nil
+}
+
+var (
+	// restoreSocketCmd puts a socket moved aside by block-socket back.
+	restoreSocketCmd = fmt.Sprintf("test ! -e %[1]s.chaos || mv -f %[1]s.chaos %[1]s", dockerSocket)
+	// clearRestoreCmd cancels a pending restore timer.
+	clearRestoreCmd = fmt.Sprintf("sh -c 'systemctl stop %[1]s.timer %[1]s.service; systemctl reset-failed %[1]s.timer %[1]s.service; true' 2>/dev/null", dockerRestoreUnit)
+)
+
+// Rollback cancels any pending restore timer and starts the services again
+// or puts the socket back.
+func (a *DockerOutageAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	nodes, err := nodesFromState(actx, "nodes")
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		runOnNode(ctx, actx.Driver, node, clearRestoreCmd)
+	}
+
+	if mode, _ := actx.State["mode"].(string); mode != "block-socket" {
+		return restoreServices(ctx, actx)
+	}
+
+	var errs []error
+	for _, node := range nodes {
+		if _, err := runOnNode(ctx, actx.Driver, node, "sh -c '"+restoreSocketCmd+"'"); err != nil {
+			errs = append(errs, fmt.Errorf("restoring docker socket on %s: %w", node.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,265 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// DrainNodeAction drains a Nomad client node through the API.
+type DrainNodeAction struct{}
+
+// Name returns the action identifier.
+func (a *DrainNodeAction) Name() string {
+	return "drain-node"
+}
+
+// Description returns a human-readable description.
+func (a *DrainNodeAction) Description() string {
+	return "Drain a Nomad client node, migrating its allocations to other nodes"
+}
+
+// Execute starts the drain and, unless wait is false, waits for it to finish.
+// The allocations running on the node beforehand are recorded so the
+// allocs-migrated assertion can follow their replacements.
+func (a *DrainNodeAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	deadline, err := params.Duration(args, "deadline", time.Minute)
+	if err != nil {
+		return err
+	}
+	if params.Bool(args, "force", false) {
+		deadline = -1
+	}
+	wait := params.Bool(args, "wait", true)
+	timeout, err := params.Duration(args, "timeout", max(deadline, 0)+time.Minute)
+	if err != nil {
+		return err
+	}
+
+	client, err := leaderClient(ctx, actx)
+	if err != nil {
+		return err
+	}
+	node, err := pickClientNode(ctx, actx, client, params.String(args, "node", "random"))
+	if err != nil {
+		return err
+	}
+
+	allocs, err := client.NodeAllocations(ctx, node.ID)
+	if err != nil {
+		return fmt.Errorf("listing allocations on %s: %w", node.Name, err)
+	}
+	migrating := migratingAllocs(allocs)
+
+	spec := &nomad.DrainSpec{
+		Deadline:         deadline,
+		IgnoreSystemJobs: params.Bool(args, "ignore_system_jobs", false),
+	}
+	actx.Details["node"] = node.Name
+	actx.Details["node_id"] = node.ID
+	actx.Details["deadline"] = deadline.String()
+	actx.Details["ignore_system_jobs"] = spec.IgnoreSystemJobs
+	actx.Details["allocs"] = migrating
+
+	start := time.Now()
+	if err := client.UpdateDrain(ctx, node.ID, spec, false); err != nil {
+		return err
+	}
+	actx.State[driver.StateNomadNodeID] = node.ID
+	actx.State[driver.StateAllocIDs] = migrating
+
+	if !wait {
+		return nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err = poll(waitCtx, 2*time.Second, func() (bool, error) {
+		nodes, err := client.Nodes(waitCtx)
+		if err != nil {
+			return false, err
+		}
+		for _, n := range nodes {
+			if n.ID == node.ID {
+				return !n.Drain, nil
+			}
+		}
+		return false, fmt.Errorf("node %s disappeared", node.Name)
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for drain of %s: %w", node.Name, err)
+	}
+
+	actx.Details["drain_time"] = time.Since(start).Round(time.Millisecond).String()
+	return nil
+}
+
+// Rollback cancels any drain still in progress and marks the node eligible.
+func (a *DrainNodeAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	nodeID, ok := actx.State[driver.StateNomadNodeID].(string)
+	if !ok {
+		return fmt.Errorf("no node recorded")
+	}
+
+	client, err := leaderClient(ctx, actx)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(
+		client.UpdateDrain(ctx, nodeID, nil, true),
+		client.UpdateEligibility(ctx, nodeID, true),
+	)
+}
+
+// migratingAllocs returns the IDs of the running allocations a drain will
+// migrate. NodeAllocations returns full allocations, so the job type comes
+// from the embedded job.
+func migratingAllocs(allocs []nomad.Allocation) []string {
+	var ids []string
+	for _, alloc := range allocs {
+		if alloc.DesiredStatus != "run" || alloc.ClientStatus != nomad.AllocClientStatusRunning {
+			continue
+		}
+		// System jobs are stopped rather than migrated
+		if t := alloc.Type(); t == "system" || t == "sysbatch" {
+			continue
+		}
+		ids = append(ids, alloc.ID)
+	}
+	return ids
+}
+
+// ToggleEligibilityAction marks a Nomad client node eligible or ineligible.
+type ToggleEligibilityAction struct{}
+
+// Name returns the action identifier.
+func (a *ToggleEligibilityAction) Name() string {
+	return "toggle-eligibility"
+}
+
+// Description returns a human-readable description.
+func (a *ToggleEligibilityAction) Description() string {
+	return "Mark a Nomad client node ineligible (or eligible) for scheduling"
+}
+
+// Execute sets the node's scheduling eligibility.
+func (a *ToggleEligibilityAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	eligible := params.Bool(args, "eligible", false)
+
+	client, err := leaderClient(ctx, actx)
+	if err != nil {
+		return err
+	}
+	node, err := pickClientNode(ctx, actx, client, params.String(args, "node", "random"))
+	if err != nil {
+		return err
+	}
+
+	actx.Details["node"] = node.Name
+	actx.Details["node_id"] = node.ID
+	actx.Details["eligibility_before"] = node.SchedulingEligibility
+
+	if err := client.UpdateEligibility(ctx, node.ID, eligible); err != nil {
+		return err
+	}
+	actx.State[driver.StateNomadNodeID] = node.ID
+	actx.State["was_eligible"] = node.SchedulingEligibility == nomad.NodeEligible
+	return nil
+}
+
+// Rollback restores the node's original eligibility.
+func (a *ToggleEligibilityAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	nodeID, ok := actx.State[driver.StateNomadNodeID].(string)
+	if !ok {
+		return fmt.Errorf("no node recorded")
+	}
+	wasEligible, _ := actx.State["was_eligible"].(bool)
+
+	client, err := leaderClient(ctx, actx)
+	if err != nil {
+		return err
+	}
+	return client.UpdateEligibility(ctx, nodeID, wasEligible)
+}
+
+// leaderClient returns a Nomad API client for the current leader.
+func leaderClient(ctx context.Context, actx *driver.ActionContext) (*nomad.Client, error) {
+	leader, err := actx.Driver.GetNomadLeader(ctx, actx.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("finding leader: %w", err)
+	}
+	return actx.Driver.NomadClient(*leader)
+}
+
+// pickClientNode finds a client node by discovered name (client-0), Nomad
+// node name, ID or ID prefix. "random" picks a ready, eligible node that is
+// not already draining.
+func pickClientNode(ctx context.Context, actx *driver.ActionContext, client *nomad.Client, name string) (*nomad.NodeListStub, error) {
+	nodes, err := client.Nodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	if discovered, err := actx.Cluster.NodeByName(name); err == nil {
+		for _, n := range nodes {
+			if n.Address == discovered.PrivateIP {
+				return &n, nil
+			}
+		}
+		return nil, fmt.Errorf("%s (%s) is not a Nomad client", name, discovered.PrivateIP)
+	}
+
+	if name == "random" {
+		var candidates []nomad.NodeListStub
+		for _, n := range nodes {
+			if n.Status == "ready" && n.SchedulingEligibility == nomad.NodeEligible && !n.Drain {
+				candidates = append(candidates, n)
+			}
+		}
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("no ready, eligible client nodes")
+		}
+		return &candidates[rand.IntN(len(candidates))], nil
+	}
+
+	var matches []nomad.NodeListStub
+	for _, n := range nodes {
+		if n.Name == name || n.ID == name {
+			return &n, nil
+		}
+		if strings.HasPrefix(n.ID, name) {
+			matches = append(matches, n)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("client node %q not found", name)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("node ID prefix %q is ambiguous", name)
+	}
+}
+
+// nomadNodeID returns the Nomad node ID of a discovered client.
+func nomadNodeID(ctx context.Context, client *nomad.Client, node driver.Node) (string, error) {
+	nodes, err := client.Nodes(ctx)
+	if err != nil {
+		return "", fmt.Errorf("listing nodes: %w", err)
+	}
+	for _, n := range nodes {
+		if n.Address == node.PrivateIP {
+			return n.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%s (%s) is not a Nomad client", node.Name, node.PrivateIP)
+}
@@ -0,0 +1,33 @@
+package actions
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+)
+
+func TestMigratingAllocs(t *testing.T) {
+	// Shaped like /v1/node/:id/allocations, which returns full allocations
+	// with the job embedded and no JobType.
+	data := `[
+		{"ID": "service", "DesiredStatus": "run", "ClientStatus": "running", "Job": {"Type": "service"}},
+		{"ID": "batch", "DesiredStatus": "run", "ClientStatus": "running", "Job": {"Type": "batch"}},
+		{"ID": "system", "DesiredStatus": "run", "ClientStatus": "running", "Job": {"Type": "system"}},
+		{"ID": "sysbatch", "DesiredStatus": "run", "ClientStatus": "running", "Job": {"Type": "sysbatch"}},
+		{"ID": "stopped", "DesiredStatus": "stop", "ClientStatus": "running", "Job": {"Type": "service"}},
+		{"ID": "pending", "DesiredStatus": "run", "ClientStatus": "pending", "Job": {"Type": "service"}},
+		{"ID": "stub", "DesiredStatus": "run", "ClientStatus": "running", "JobType": "system"}
+	]`
+	var allocs []nomad.Allocation
+	if err := json.Unmarshal([]byte(data), &allocs); err != nil {
+		t.Fatal(err)
+	}
+
+	got := migratingAllocs(allocs)
+	want := []string{"service", "batch"}
+	if !slices.Equal(got, want) {
+		t.Errorf("migratingAllocs = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,131 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+)
+
+// runSudo runs a command with sudo and returns its stdout, treating a
+// non-zero exit code as an error.
+func runSudo(ctx context.Context, client driver.SSHClient, cmd string) (string, error) {
+	stdout, stderr, exitCode, err := client.RunWithSudo(ctx, cmd)
+	if err != nil {
+		return "", fmt.Errorf("executing %q: %w", cmd, err)
+	}
+	if exitCode != 0 {
+		return stdout, fmt.Errorf("%q failed (exit %d): %s", cmd, exitCode, strings.TrimSpace(stderr))
+	}
+	return stdout, nil
+}
+
+// runOnNode opens an SSH connection to a node and runs a single sudo command.
+func runOnNode(ctx context.Context, drv driver.Driver, node driver.Node, cmd string) (string, error) {
+	client, err := drv.SSH(ctx, node)
+	if err != nil {
+		return "", fmt.Errorf("connecting to %s: %w", node.Name, err)
+	}
+	defer client.Close()
+
+	return runSudo(ctx, client, cmd)
+}
+
+// resolveNodes expands node selectors into cluster nodes; see
+// driver.SelectNodes.
+func resolveNodes(ctx context.Context, actx *driver.ActionContext, selectors []string) ([]driver.Node, error) {
+	return driver.SelectNodes(ctx, actx.Driver, actx.Cluster, selectors)
+}
+
+// nodeNames returns the names of the given nodes.
+func nodeNames(nodes []driver.Node) []string {
+	names := make([]string, len(nodes))
+	for i, n := range nodes {
+		names[i] = n.Name
+	}
+	return names
+}
+
+// nodesFromState resolves node names recorded in action state back to nodes.
+func nodesFromState(actx *driver.ActionContext, key string) ([]driver.Node, error) {
+	names, ok := actx.State[key].([]string)
+	if !ok || len(names) == 0 {
+		return nil, fmt.Errorf("no target nodes recorded")
+	}
+
+	nodes := make([]driver.Node, 0, len(names))
+	for _, name := range names {
+		node, err := actx.Cluster.NodeByName(name)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, *node)
+	}
+	return nodes, nil
+}
+
+// stopTransientUnit stops a chaos-owned systemd unit. Units that already
+// exited (and were collected) are not an error.
+func stopTransientUnit(ctx context.Context, client driver.SSHClient, unit string) error {
+	_, _, _, err := client.RunWithSudo(ctx, fmt.Sprintf("sh -c 'systemctl stop %s; systemctl reset-failed %s' 2>/dev/null", unit, unit))
+	return err
+}
+
+// poll calls check every interval until it reports done or ctx expires.
+// The last check error is returned on timeout to explain what was pending.
+func poll(ctx context.Context, interval time.Duration, check func() (bool, error)) error {
+	var lastErr error
+	for {
+		done, err := check()
+		if done {
+			return nil
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("%w (last error: %v)", ctx.Err(), lastErr)
+			}
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// runUndo runs the per-node undo commands recorded in State["undo"] by
+// actions that apply several steps, newest first. Commands that succeed are
+// forgotten, so a second rollback only retries what failed.
+func runUndo(ctx context.Context, actx *driver.ActionContext) error {
+	nodes, err := nodesFromState(actx, "nodes")
+	if err != nil {
+		return err
+	}
+	undo, _ := actx.State["undo"].(map[string][]string)
+
+	var errs []error
+	for _, node := range nodes {
+		cmds := undo[node.Name]
+		if len(cmds) == 0 {
+			continue
+		}
+		client, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("connecting to %s: %w", node.Name, err))
+			continue
+		}
+		var failed []string
+		for i := len(cmds) - 1; i >= 0; i-- {
+			if _, err := runSudo(ctx, client, cmds[i]); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", node.Name, cmds[i], err))
+				failed = append([]string{cmds[i]}, failed...)
+			}
+		}
+		undo[node.Name] = failed
+		client.Close()
+	}
+	return errors.Join(errs...)
+}
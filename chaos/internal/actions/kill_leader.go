@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/libvirt-standalone/chaos/internal/driver"
 )
@@ -42,6 +43,7 @@ func (a *KillLeaderAction) Execute(ctx context.Context, actx *driver.ActionConte
 	// Store for potential rollback info (though we can't really restore a killed process)
 	actx.State["killed_node"] = leader.Name
 	actx.State["killed_ip"] = leader.PublicIP
+	actx.State[driver.StateLeader] = leader.Name
 
 	// SSH to the leader and kill the process
 	client, err := actx.Driver.SSH(ctx, *leader)
@@ -50,6 +52,8 @@ func (a *KillLeaderAction) Execute(ctx context.Context, actx *driver.ActionConte
 	}
 	defer client.Close()
 
+	actx.State[driver.StateFaultTime] = time.Now()
+
 	// Stop nomad via systemctl so systemd doesn't auto-restart it.
 	// Using pkill alone is insufficient because the systemd unit has
 	// Restart=on-failure with RestartSec=2, so the process comes back
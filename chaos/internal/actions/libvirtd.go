@@ -0,0 +1,133 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+	"github.com/libvirt-standalone/chaos/internal/virt"
+)
+
+// LibvirtdOutageAction stops, restarts or kills libvirtd and related
+// services on clients while their VMs keep running.
+type LibvirtdOutageAction struct{}
+
+// Name returns the action identifier.
+func (a *LibvirtdOutageAction) Name() string {
+	return "libvirtd-outage"
+}
+
+// Description returns a human-readable description.
+func (a *LibvirtdOutageAction) Description() string {
+	return "Stop, restart or kill libvirtd (and optionally virtlogd or nomad) on clients, recording running VMs first"
+}
+
+// Execute records the virt tasks running on each client, then faults the
+// services. In stop mode the socket units are stopped too, since socket
+// activation would otherwise start libvirtd again on the driver's next
+// call. With a duration the services are started again before returning,
+// so later assertions see the recovery.
+func (a *LibvirtdOutageAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	mode := params.String(args, "mode", "stop")
+	if mode != "stop" && mode != "restart" && mode != "kill" {
+		return fmt.Errorf("invalid mode %q: must be stop, restart or kill", mode)
+	}
+	duration, err := params.Duration(args, "duration", 0)
+	if err != nil {
+		return err
+	}
+	services := params.StringSlice(args, "services")
+	if len(services) == 0 {
+		services = []string{"libvirtd"}
+	}
+
+	selectors := params.StringSlice(args, "nodes")
+	if len(selectors) == 0 {
+		selectors = []string{"clients"}
+	}
+	nodes, err := resolveNodes(ctx, actx, selectors)
+	if err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		if n.Role != driver.RoleClient {
+			return fmt.Errorf("%s is not a client", n.Name)
+		}
+	}
+
+	baseline, err := recordVirtDomains(ctx, actx, nodes)
+	if err != nil {
+		return err
+	}
+	actx.State[driver.StateVirtDomains] = baseline
+	actx.Details["domains_before"] = baseline
+
+	var allocIDs []string
+	for _, d := range baseline {
+		if !slices.Contains(allocIDs, d.AllocID) {
+			allocIDs = append(allocIDs, d.AllocID)
+		}
+	}
+	actx.State[driver.StateAllocIDs] = allocIDs
+	actx.State[driver.StateFaultTime] = time.Now()
+
+	if err := faultServices(ctx, actx, nodes, mode, services); err != nil {
+		return err
+	}
+	return holdOutage(ctx, actx, duration, func() error { return a.Rollback(ctx, actx) })
+}
+
+// Rollback starts the stopped units again and waits for the services.
+func (a *LibvirtdOutageAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	return restoreServices(ctx, actx)
+}
+
+// recordVirtDomains lists the running allocations on each client together
+// with the libvirt domain of each virt task. Allocations without virt tasks
+// are skipped.
+func recordVirtDomains(ctx context.Context, actx *driver.ActionContext, nodes []driver.Node) ([]virt.AllocDomain, error) {
+	client, err := leaderClient(ctx, actx)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []virt.AllocDomain
+	for _, node := range nodes {
+		nodeID, err := nomadNodeID(ctx, client, node)
+		if err != nil {
+			return nil, err
+		}
+
+		allocs, err := client.NodeAllocations(ctx, nodeID)
+		if err != nil {
+			return nil, fmt.Errorf("listing allocations on %s: %w", node.Name, err)
+		}
+
+		ssh, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			return nil, fmt.Errorf("connecting to %s: %w", node.Name, err)
+		}
+		for _, alloc := range allocs {
+			tasks := virt.VirtTasks(alloc.TaskDrivers())
+			if alloc.ClientStatus != nomad.AllocClientStatusRunning || len(tasks) == 0 {
+				continue
+			}
+			domains, err := virt.AllocDomains(ctx, ssh, alloc.ID)
+			if err != nil {
+				ssh.Close()
+				return nil, fmt.Errorf("listing domains on %s: %w", node.Name, err)
+			}
+			for _, task := range tasks {
+				for _, d := range virt.TaskDomains(domains, task, len(tasks)) {
+					out = append(out, virt.AllocDomain{AllocID: alloc.ID, Task: task, Node: node.Name, Domain: d})
+				}
+			}
+		}
+		ssh.Close()
+	}
+	return out, nil
+}
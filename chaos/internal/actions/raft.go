@@ -0,0 +1,349 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// TransferLeadershipAction hands raft leadership to a chosen follower.
+type TransferLeadershipAction struct{}
+
+// Name returns the action identifier.
+func (a *TransferLeadershipAction) Name() string {
+	return "transfer-leadership"
+}
+
+// Description returns a human-readable description.
+func (a *TransferLeadershipAction) Description() string {
+	return "Transfer Nomad raft leadership to a chosen or random follower"
+}
+
+// Execute transfers leadership and waits until the target is leader.
+func (a *TransferLeadershipAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	timeout, err := params.Duration(args, "timeout", 30*time.Second)
+	if err != nil {
+		return err
+	}
+
+	leader, err := actx.Driver.GetNomadLeader(ctx, actx.Cluster)
+	if err != nil {
+		return fmt.Errorf("finding leader: %w", err)
+	}
+
+	target, err := pickFollower(actx.Cluster, leader.Name, params.String(args, "target", "random"))
+	if err != nil {
+		return err
+	}
+
+	actx.State["previous_leader"] = leader.Name
+	actx.State["timeout"] = timeout
+	actx.Details["previous_leader"] = leader.Name
+	actx.Details["target"] = target.Name
+
+	elapsed, err := transferLeadership(ctx, actx, *leader, *target, timeout)
+	if err != nil {
+		return err
+	}
+
+	actx.Details["new_leader"] = target.Name
+	actx.Details["transfer_time"] = elapsed.String()
+	return nil
+}
+
+// Rollback hands leadership back to the original leader.
+func (a *TransferLeadershipAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	name, ok := actx.State["previous_leader"].(string)
+	if !ok {
+		return fmt.Errorf("no previous leader recorded")
+	}
+	timeout, _ := actx.State["timeout"].(time.Duration)
+
+	previous, err := actx.Cluster.ServerByName(name)
+	if err != nil {
+		return err
+	}
+
+	leader, err := actx.Driver.GetNomadLeader(ctx, actx.Cluster)
+	if err != nil {
+		return fmt.Errorf("finding leader: %w", err)
+	}
+	if leader.Name == previous.Name {
+		return nil
+	}
+
+	_, err = transferLeadership(ctx, actx, *leader, *previous, timeout)
+	return err
+}
+
+// transferLeadership asks the leader to hand over to target, then polls
+// until target is reported as leader.
+func transferLeadership(ctx context.Context, actx *driver.ActionContext, leader, target driver.Node, timeout time.Duration) (time.Duration, error) {
+	client, err := actx.Driver.NomadClient(leader)
+	if err != nil {
+		return 0, err
+	}
+
+	raft, err := client.RaftConfiguration(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("reading raft configuration: %w", err)
+	}
+	peer, ok := raft.ServerByIP(target.PrivateIP)
+	if !ok {
+		return 0, fmt.Errorf("%s (%s) is not in the raft configuration", target.Name, target.PrivateIP)
+	}
+	if !peer.Voter {
+		return 0, fmt.Errorf("%s is not a raft voter", target.Name)
+	}
+
+	start := time.Now()
+	if err := client.TransferLeadership(ctx, peer.ID); err != nil {
+		return 0, err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err = poll(waitCtx, time.Second, func() (bool, error) {
+		current, err := actx.Driver.GetNomadLeader(waitCtx, actx.Cluster)
+		if err != nil {
+			return false, err
+		}
+		if current.Name != target.Name {
+			return false, fmt.Errorf("leader is %s", current.Name)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("waiting for %s to become leader: %w", target.Name, err)
+	}
+
+	return time.Since(start), nil
+}
+
+// rejoinGrace is how long a restarted server gets to rejoin through Consul
+// before it is joined explicitly.
+const rejoinGrace = 20 * time.Second
+
+// RaftRemovePeerAction removes a server from the raft configuration.
+type RaftRemovePeerAction struct{}
+
+// Name returns the action identifier.
+func (a *RaftRemovePeerAction) Name() string {
+	return "raft-remove-peer"
+}
+
+// Description returns a human-readable description.
+func (a *RaftRemovePeerAction) Description() string {
+	return "Remove a Nomad server from the raft configuration; rollback restarts and rejoins it"
+}
+
+// Execute stops the server's agent (optionally) and removes its raft peer.
+// When the target is the leader the removal waits for the remaining
+// servers to elect a new one.
+func (a *RaftRemovePeerAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	timeout, err := params.Duration(args, "timeout", 30*time.Second)
+	if err != nil {
+		return err
+	}
+
+	// A running server is re-added by the leader's serf reconciliation, so
+	// stop the agent first unless the caller wants to observe that race.
+	stopAgent := params.Bool(args, "stop_agent", true)
+
+	leader, err := actx.Driver.GetNomadLeader(ctx, actx.Cluster)
+	if err != nil {
+		return fmt.Errorf("finding leader: %w", err)
+	}
+
+	var target *driver.Node
+	switch name := params.String(args, "node", "random"); name {
+	case "leader":
+		target = leader
+	default:
+		target, err = pickFollower(actx.Cluster, leader.Name, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	client, err := nomadClientExcluding(actx, target.Name)
+	if err != nil {
+		return err
+	}
+
+	raft, err := client.RaftConfiguration(ctx)
+	if err != nil {
+		return fmt.Errorf("reading raft configuration: %w", err)
+	}
+	peer, ok := raft.ServerByIP(target.PrivateIP)
+	if !ok {
+		return fmt.Errorf("%s (%s) is not in the raft configuration", target.Name, target.PrivateIP)
+	}
+
+	actx.State["node"] = target.Name
+	actx.State["peer_id"] = peer.ID
+	actx.State["timeout"] = timeout
+	actx.Details["node"] = target.Name
+	actx.Details["peer_id"] = peer.ID
+	actx.Details["peer_address"] = peer.Address
+	actx.Details["peers_before"] = len(raft.Servers)
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if stopAgent {
+		if _, err := runOnNode(ctx, actx.Driver, *target, "systemctl stop nomad"); err != nil {
+			return fmt.Errorf("stopping nomad on %s: %w", target.Name, err)
+		}
+	}
+
+	// Removing the stopped leader's peer needs a quorum with a new leader,
+	// otherwise the request fails with no cluster leader
+	if stopAgent && target.Name == leader.Name {
+		start := time.Now()
+		err := poll(waitCtx, time.Second, func() (bool, error) {
+			current, err := actx.Driver.GetNomadLeader(waitCtx, actx.Cluster)
+			if err != nil {
+				return false, err
+			}
+			if current.Name == target.Name {
+				return false, fmt.Errorf("leader is still %s", current.Name)
+			}
+			actx.Details["new_leader"] = current.Name
+			return true, nil
+		})
+		if err != nil {
+			return fmt.Errorf("waiting for a new leader after stopping %s: %w", target.Name, err)
+		}
+		actx.Details["election_time"] = time.Since(start).Round(time.Millisecond).String()
+	}
+
+	if err := client.RemoveRaftPeer(ctx, peer.ID); err != nil {
+		return err
+	}
+
+	var peersAfter int
+	err = poll(waitCtx, time.Second, func() (bool, error) {
+		raft, err := client.RaftConfiguration(waitCtx)
+		if err != nil {
+			return false, err
+		}
+		peersAfter = len(raft.Servers)
+		if _, ok := raft.ServerByIP(target.PrivateIP); ok {
+			return false, fmt.Errorf("%s still in raft configuration", target.Name)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("verifying removal of %s: %w", target.Name, err)
+	}
+
+	actx.Details["peers_after"] = peersAfter
+	return nil
+}
+
+// Rollback restarts the removed server and waits for it to rejoin as a voter.
+func (a *RaftRemovePeerAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	name, ok := actx.State["node"].(string)
+	if !ok {
+		return fmt.Errorf("no removed node recorded")
+	}
+	timeout, _ := actx.State["timeout"].(time.Duration)
+	if timeout < time.Minute {
+		timeout = time.Minute
+	}
+
+	node, err := actx.Cluster.ServerByName(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := runOnNode(ctx, actx.Driver, *node, "systemctl restart nomad"); err != nil {
+		return fmt.Errorf("restarting nomad on %s: %w", name, err)
+	}
+
+	client, err := nomadClientExcluding(actx, name)
+	if err != nil {
+		return err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The lab's nomad.hcl has no server_join retry_join: servers find each
+	// other through Consul auto-join, which only searches for servers while
+	// the agent has no cluster leader. The restarted server still has serf
+	// peers from before, so it usually rejoins by itself; if it has not
+	// after a grace period an explicit join against the other servers' serf
+	// addresses does what retry_join would.
+	start := time.Now()
+	return poll(waitCtx, 2*time.Second, func() (bool, error) {
+		raft, err := client.RaftConfiguration(waitCtx)
+		if err != nil {
+			return false, err
+		}
+		if peer, ok := raft.ServerByIP(node.PrivateIP); ok && peer.Voter {
+			return true, nil
+		}
+
+		if time.Since(start) > rejoinGrace {
+			if agent, err := actx.Driver.NomadClient(*node); err == nil {
+				_, _ = agent.Join(waitCtx, serfAddrs(actx.Cluster, name)...)
+			}
+		}
+		return false, fmt.Errorf("%s not yet a raft voter", name)
+	})
+}
+
+// pickFollower resolves a server name, or "random", to a non-leader server.
+func pickFollower(cluster *driver.Cluster, leaderName, name string) (*driver.Node, error) {
+	if name != "random" {
+		node, err := cluster.ServerByName(name)
+		if err != nil {
+			return nil, err
+		}
+		if node.Name == leaderName {
+			return nil, fmt.Errorf("%s is already the leader", name)
+		}
+		return node, nil
+	}
+
+	var followers []*driver.Node
+	for i := range cluster.Servers {
+		if cluster.Servers[i].Name != leaderName {
+			followers = append(followers, &cluster.Servers[i])
+		}
+	}
+	if len(followers) == 0 {
+		return nil, fmt.Errorf("no followers available")
+	}
+	return followers[rand.IntN(len(followers))], nil
+}
+
+// nomadClientExcluding returns a client for the first server other than
+// the named one, for querying the cluster while that server is faulted.
+func nomadClientExcluding(actx *driver.ActionContext, exclude string) (*nomad.Client, error) {
+	for _, s := range actx.Cluster.Servers {
+		if s.Name != exclude {
+			return actx.Driver.NomadClient(s)
+		}
+	}
+	return nil, fmt.Errorf("no server available besides %s", exclude)
+}
+
+// serfAddrs returns the serf addresses of all servers except the named one.
+func serfAddrs(cluster *driver.Cluster, exclude string) []string {
+	var addrs []string
+	for _, s := range cluster.Servers {
+		if s.Name != exclude {
+			addrs = append(addrs, s.PrivateIP+":4648")
+		}
+	}
+	return addrs
+}
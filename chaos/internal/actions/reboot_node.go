@@ -0,0 +1,193 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// RebootNodeAction reboots nodes and waits for them to come back.
+type RebootNodeAction struct{}
+
+// Name returns the action identifier.
+func (a *RebootNodeAction) Name() string {
+	return "reboot-node"
+}
+
+// Description returns a human-readable description.
+func (a *RebootNodeAction) Description() string {
+	return "Reboot nodes gracefully or via sysrq, then wait for SSH and services to return"
+}
+
+// Execute triggers the reboot and blocks until every node is back. The wait
+// must fit in ctx, so it fails before rebooting anything when the deadline
+// is closer than timeout, e.g. under the default inject --timeout.
+func (a *RebootNodeAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	mode := params.String(args, "mode", "graceful")
+	var trigger string
+	switch mode {
+	case "graceful":
+		trigger = "systemd-run --on-active=2 systemctl reboot"
+	case "forced":
+		// sysrq 'b' reboots immediately without syncing or unmounting
+		trigger = `sh -c 'echo 1 > /proc/sys/kernel/sysrq && systemd-run --on-active=2 sh -c "echo b > /proc/sysrq-trigger"'`
+	default:
+		return fmt.Errorf("invalid mode %q: must be graceful or forced", mode)
+	}
+
+	timeout, err := params.Duration(args, "timeout", 5*time.Minute)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := time.Until(deadline); left < timeout {
+			return fmt.Errorf("waiting up to %s for nodes to return needs more than the %s left before the deadline: raise --timeout or lower the timeout arg", timeout, left.Round(time.Second))
+		}
+	}
+
+	services := params.StringSlice(args, "services")
+	if len(services) == 0 {
+		services = []string{"nomad"}
+	}
+
+	selectors := params.StringSlice(args, "nodes")
+	if len(selectors) == 0 {
+		selectors = []string{"leader"}
+	}
+	nodes, err := resolveNodes(ctx, actx, selectors)
+	if err != nil {
+		return err
+	}
+
+	actx.State["nodes"] = nodeNames(nodes)
+	actx.State["services"] = services
+	actx.Details["mode"] = mode
+	actx.Details["services"] = services
+
+	// Boot IDs tell a rebooted node apart from one that never went down
+	bootIDs := make(map[string]string)
+	rebootedAt := make(map[string]time.Time)
+	for _, node := range nodes {
+		client, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			return fmt.Errorf("connecting to %s: %w", node.Name, err)
+		}
+		bootID, err := readBootID(ctx, client)
+		if err == nil {
+			rebootedAt[node.Name] = time.Now()
+			_, err = runSudo(ctx, client, trigger)
+		}
+		client.Close()
+		if err != nil {
+			return fmt.Errorf("rebooting %s: %w", node.Name, err)
+		}
+		bootIDs[node.Name] = bootID
+	}
+
+	sshReturn := make(map[string]string)
+	servicesReturn := make(map[string]string)
+	actx.Details["time_to_ssh"] = sshReturn
+	actx.Details["time_to_services"] = servicesReturn
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var errs []error
+	for _, node := range nodes {
+		if err := waitForReboot(waitCtx, actx.Driver, node, bootIDs[node.Name]); err != nil {
+			errs = append(errs, fmt.Errorf("%s did not return over SSH: %w", node.Name, err))
+			continue
+		}
+		sshReturn[node.Name] = time.Since(rebootedAt[node.Name]).Round(time.Second).String()
+
+		if err := waitForServices(waitCtx, actx.Driver, node, services); err != nil {
+			errs = append(errs, fmt.Errorf("%s services not active: %w", node.Name, err))
+			continue
+		}
+		servicesReturn[node.Name] = time.Since(rebootedAt[node.Name]).Round(time.Second).String()
+	}
+
+	return errors.Join(errs...)
+}
+
+// Rollback starts any services that did not come back after the reboot.
+func (a *RebootNodeAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	nodes, err := nodesFromState(actx, "nodes")
+	if err != nil {
+		return err
+	}
+	services, _ := actx.State["services"].([]string)
+
+	var errs []error
+	for _, node := range nodes {
+		cmd := "systemctl start " + strings.Join(services, " ")
+		if _, err := runOnNode(ctx, actx.Driver, node, cmd); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", node.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// readBootID returns the kernel boot ID, which changes on every boot.
+func readBootID(ctx context.Context, client driver.SSHClient) (string, error) {
+	out, _, exitCode, err := client.Run(ctx, "cat /proc/sys/kernel/random/boot_id")
+	if err != nil {
+		return "", err
+	}
+	if exitCode != 0 {
+		return "", fmt.Errorf("reading boot_id failed (exit %d)", exitCode)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// waitForReboot polls SSH until the node answers with a new boot ID.
+// Driver.SSH dials a new connection on every call and caches nothing, so
+// no connection from before the reboot is reused and there is no cache to
+// invalidate.
+func waitForReboot(ctx context.Context, drv driver.Driver, node driver.Node, oldBootID string) error {
+	return poll(ctx, 5*time.Second, func() (bool, error) {
+		client, err := drv.SSH(ctx, node)
+		if err != nil {
+			return false, err
+		}
+		defer client.Close()
+
+		bootID, err := readBootID(ctx, client)
+		if err != nil {
+			return false, err
+		}
+		if bootID == oldBootID {
+			return false, fmt.Errorf("node has not rebooted yet")
+		}
+		return true, nil
+	})
+}
+
+// waitForServices polls until every service reports active.
+func waitForServices(ctx context.Context, drv driver.Driver, node driver.Node, services []string) error {
+	return poll(ctx, 2*time.Second, func() (bool, error) {
+		client, err := drv.SSH(ctx, node)
+		if err != nil {
+			return false, err
+		}
+		defer client.Close()
+
+		out, _, _, err := client.Run(ctx, "systemctl is-active "+strings.Join(services, " "))
+		if err != nil {
+			return false, err
+		}
+		states := strings.Fields(out)
+		for i, state := range states {
+			if state != "active" && i < len(services) {
+				return false, fmt.Errorf("%s is %s", services[i], state)
+			}
+		}
+		return len(states) == len(services), nil
+	})
+}
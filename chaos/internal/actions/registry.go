@@ -91,5 +91,30 @@ func List() []string {
 func init() {
 	// Register all built-in actions
 	Register(&KillLeaderAction{})
+	Register(&CaptureLeaderAction{})
 	Register(&PartitionAction{})
+	Register(&ClockSkewAction{})
+	Register(&CPUStressAction{})
+	Register(&MemoryPressureAction{})
+	Register(&DiskFillAction{})
+	Register(&IOThrottleAction{})
+	Register(&ReadonlyRemountAction{})
+	Register(&RebootNodeAction{})
+	Register(&TransferLeadershipAction{})
+	Register(&RaftRemovePeerAction{})
+	Register(&ServerLeaveAction{})
+	Register(&SnapshotAction{})
+	Register(&WipeRaftDataAction{})
+	Register(&RestoreSnapshotAction{})
+	Register(&DrainNodeAction{})
+	Register(&ToggleEligibilityAction{})
+	Register(&AllocStopAction{})
+	Register(&AllocRestartAction{})
+	Register(&AllocKillAction{})
+	Register(&VirtDomainAction{})
+	Register(&LibvirtdOutageAction{})
+	Register(&VirtImageStorageAction{})
+	Register(&ArtifactOutageAction{})
+	Register(&DockerOutageAction{})
+	Register(&DNSFaultAction{})
 }
@@ -0,0 +1,256 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+const (
+	cpuStressUnit      = "chaos-cpu-stress"
+	memoryPressureUnit = "chaos-memory-pressure"
+)
+
+// CPUStressAction burns CPU on nodes or caps the CPU quota of a service.
+type CPUStressAction struct{}
+
+// Name returns the action identifier.
+func (a *CPUStressAction) Name() string {
+	return "cpu-stress"
+}
+
+// Description returns a human-readable description.
+func (a *CPUStressAction) Description() string {
+	return "Saturate node CPUs with a stressor, or throttle a service with CPUQuota"
+}
+
+// Execute starts the stressor or applies the CPU quota.
+func (a *CPUStressAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	workers := params.Int(args, "workers", 0) // 0 = one per CPU
+	load := params.Int(args, "load", 100)
+	if load < 1 || load > 100 {
+		return fmt.Errorf("invalid load %d: must be between 1 and 100", load)
+	}
+
+	return executeResourceFault(ctx, actx, args, resourceFault{
+		unit:     cpuStressUnit,
+		property: "CPUQuota",
+		showProp: "CPUQuotaPerSecUSec",
+		limitArg: "cpu_quota",
+		stressNG: fmt.Sprintf("stress-ng --cpu %d --cpu-load %d", workers, load),
+		builtin: fmt.Sprintf(`n=%d; [ "$n" -gt 0 ] || n=$(nproc); for i in $(seq $n); do (while :; do :; done) & done; wait`,
+			workers),
+	})
+}
+
+// Rollback stops the stressor or restores the original CPU quota.
+func (a *CPUStressAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	return rollbackResourceFault(ctx, actx)
+}
+
+// MemoryPressureAction consumes memory on nodes or caps a service's MemoryMax.
+type MemoryPressureAction struct{}
+
+// Name returns the action identifier.
+func (a *MemoryPressureAction) Name() string {
+	return "memory-pressure"
+}
+
+// Description returns a human-readable description.
+func (a *MemoryPressureAction) Description() string {
+	return "Allocate node memory with a stressor, or throttle a service with MemoryMax"
+}
+
+// Execute starts the allocation or applies the memory limit.
+func (a *MemoryPressureAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	size := params.String(args, "size", "80%")
+
+	// head -c takes K-suffixed sizes; percentages are resolved from MemTotal
+	builtinSize := size
+	if pct, ok := strings.CutSuffix(size, "%"); ok {
+		builtinSize = fmt.Sprintf(`$(( $(awk "/MemTotal/ {print \$2}" /proc/meminfo) * %s / 100 ))K`, pct)
+	}
+
+	return executeResourceFault(ctx, actx, args, resourceFault{
+		unit:     memoryPressureUnit,
+		property: "MemoryMax",
+		showProp: "MemoryMax",
+		limitArg: "memory_max",
+		stressNG: fmt.Sprintf("stress-ng --vm 1 --vm-bytes %s --vm-keep", size),
+		// tail buffers the newline-free stream in memory, and blocks
+		// writing to sleep, so the allocation is held until stopped.
+		builtin: fmt.Sprintf("head -c %s /dev/zero | tail | sleep infinity", builtinSize),
+	})
+}
+
+// Rollback frees the allocation or restores the original memory limit.
+func (a *MemoryPressureAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	return rollbackResourceFault(ctx, actx)
+}
+
+// resourceFault describes the commands and properties for one resource type.
+type resourceFault struct {
+	unit     string // transient unit running the stressor
+	property string // systemctl set-property name
+	showProp string // systemctl show name for reading the current value
+	limitArg string // argument holding the throttle value
+	stressNG string // stress-ng invocation
+	builtin  string // shell fallback when stress-ng is missing
+}
+
+// executeResourceFault applies a stress or throttle fault to the target nodes.
+func executeResourceFault(ctx context.Context, actx *driver.ActionContext, args map[string]any, f resourceFault) error {
+	mode := params.String(args, "mode", "stress")
+	if mode != "stress" && mode != "throttle" {
+		return fmt.Errorf("invalid mode %q: must be stress or throttle", mode)
+	}
+
+	duration, err := params.Duration(args, "duration", 0)
+	if err != nil {
+		return err
+	}
+
+	service := params.String(args, "service", "nomad")
+	limit := params.String(args, f.limitArg, "")
+	if mode == "throttle" && limit == "" {
+		return fmt.Errorf("%s is required in throttle mode", f.limitArg)
+	}
+
+	selectors := params.StringSlice(args, "nodes")
+	if len(selectors) == 0 {
+		selectors = []string{"leader"}
+	}
+	nodes, err := resolveNodes(ctx, actx, selectors)
+	if err != nil {
+		return err
+	}
+
+	actx.State["nodes"] = nodeNames(nodes)
+	actx.State["mode"] = mode
+	actx.State["fault"] = f
+	actx.State["service"] = service
+	actx.Details["mode"] = mode
+
+	original := make(map[string]string)
+	actx.State["original"] = original
+	stressors := make(map[string]string)
+
+	for _, node := range nodes {
+		client, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			return fmt.Errorf("connecting to %s: %w", node.Name, err)
+		}
+
+		if mode == "throttle" {
+			value, err := runSudo(ctx, client, fmt.Sprintf("systemctl show %s -p %s --value", service, f.showProp))
+			if err != nil {
+				client.Close()
+				return fmt.Errorf("reading %s on %s: %w", f.property, node.Name, err)
+			}
+			original[node.Name] = strings.TrimSpace(value)
+
+			cmd := fmt.Sprintf("systemctl set-property --runtime %s %s=%s", service, f.property, limit)
+			if _, err := runSudo(ctx, client, cmd); err != nil {
+				client.Close()
+				return fmt.Errorf("throttling %s on %s: %w", service, node.Name, err)
+			}
+			client.Close()
+			continue
+		}
+
+		// Clear leftovers from an earlier run so systemd-run can reuse the name
+		if err := stopTransientUnit(ctx, client, f.unit); err != nil {
+			client.Close()
+			return fmt.Errorf("preparing %s: %w", node.Name, err)
+		}
+
+		script := f.builtin
+		stressors[node.Name] = "builtin"
+		if _, _, exitCode, err := client.Run(ctx, "command -v stress-ng"); err == nil && exitCode == 0 {
+			script = f.stressNG
+			stressors[node.Name] = "stress-ng"
+		}
+
+		cmd := fmt.Sprintf("systemd-run --unit=%s --collect", f.unit)
+		if duration > 0 {
+			cmd += fmt.Sprintf(" -p RuntimeMaxSec=%d", int(duration/time.Second))
+		}
+		cmd += fmt.Sprintf(" sh -c '%s'", script)
+
+		_, err = runSudo(ctx, client, cmd)
+		client.Close()
+		if err != nil {
+			return fmt.Errorf("starting stressor on %s: %w", node.Name, err)
+		}
+	}
+
+	if mode == "throttle" {
+		actx.Details["service"] = service
+		actx.Details[f.limitArg] = limit
+		actx.Details["original_"+f.limitArg] = original
+	} else {
+		actx.Details["stressor"] = stressors
+	}
+
+	return nil
+}
+
+// rollbackResourceFault stops stressors or restores throttled properties.
+func rollbackResourceFault(ctx context.Context, actx *driver.ActionContext) error {
+	nodes, err := nodesFromState(actx, "nodes")
+	if err != nil {
+		return err
+	}
+	f, _ := actx.State["fault"].(resourceFault)
+	mode, _ := actx.State["mode"].(string)
+	service, _ := actx.State["service"].(string)
+	original, _ := actx.State["original"].(map[string]string)
+
+	var errs []error
+	for _, node := range nodes {
+		client, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("connecting to %s: %w", node.Name, err))
+			continue
+		}
+
+		if mode == "throttle" {
+			value, ok := original[node.Name]
+			if !ok {
+				// Execute failed before this node was throttled
+				client.Close()
+				continue
+			}
+			cmd := fmt.Sprintf("systemctl set-property --runtime %s %s=%s", service, f.property, propertyValue(f.property, value))
+			if _, err := runSudo(ctx, client, cmd); err != nil {
+				errs = append(errs, fmt.Errorf("restoring %s on %s: %w", f.property, node.Name, err))
+			}
+		} else if err := stopTransientUnit(ctx, client, f.unit); err != nil {
+			errs = append(errs, fmt.Errorf("stopping stressor on %s: %w", node.Name, err))
+		}
+		client.Close()
+	}
+
+	return errors.Join(errs...)
+}
+
+// propertyValue converts a value read with systemctl show into the form
+// accepted by systemctl set-property.
+func propertyValue(property, shown string) string {
+	if shown == "" || shown == "infinity" {
+		// An empty assignment resets the property to its default
+		return ""
+	}
+	if property == "CPUQuota" {
+		// CPUQuotaPerSecUSec is CPU time per wall second, e.g. "500ms" = 50%
+		if d, err := time.ParseDuration(shown); err == nil {
+			return fmt.Sprintf("%d%%", d*100/time.Second)
+		}
+	}
+	return shown
+}
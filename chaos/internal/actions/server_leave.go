@@ -0,0 +1,178 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// leaveConfigFile makes the agent leave the gossip pool when it is stopped.
+// Nomad reads it at startup, so the agent is restarted once to pick it up.
+const leaveConfigFile = "/etc/nomad.d/zz-chaos-leave.hcl"
+
+// ServerLeaveAction removes a server from the cluster membership.
+type ServerLeaveAction struct{}
+
+// Name returns the action identifier.
+func (a *ServerLeaveAction) Name() string {
+	return "server-leave"
+}
+
+// Description returns a human-readable description.
+func (a *ServerLeaveAction) Description() string {
+	return "Make a Nomad server leave gracefully, or force-leave it from another server while running"
+}
+
+// Execute makes the target server leave and captures autopilot health.
+func (a *ServerLeaveAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	mode := params.String(args, "mode", "graceful")
+	if mode != "graceful" && mode != "force" {
+		return fmt.Errorf("invalid mode %q: must be graceful or force", mode)
+	}
+
+	// Time for autopilot to notice the change before capturing health
+	settle, err := params.Duration(args, "settle", 10*time.Second)
+	if err != nil {
+		return err
+	}
+
+	leader, err := actx.Driver.GetNomadLeader(ctx, actx.Cluster)
+	if err != nil {
+		return fmt.Errorf("finding leader: %w", err)
+	}
+
+	var target *driver.Node
+	switch name := params.String(args, "node", "random"); name {
+	case "leader":
+		target = leader
+	default:
+		target, err = pickFollower(actx.Cluster, leader.Name, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	client, err := nomadClientExcluding(actx, target.Name)
+	if err != nil {
+		return err
+	}
+
+	actx.State["node"] = target.Name
+	actx.State["mode"] = mode
+	actx.Details["node"] = target.Name
+	actx.Details["mode"] = mode
+	captureAutopilot(ctx, client, actx.Details, "autopilot_before")
+
+	switch mode {
+	case "graceful":
+		cmd := fmt.Sprintf(`sh -c 'printf "leave_on_interrupt = true\nleave_on_terminate = true\n" > %s && systemctl restart nomad && sleep 5 && systemctl stop nomad'`,
+			leaveConfigFile)
+		if _, err := runOnNode(ctx, actx.Driver, *target, cmd); err != nil {
+			return fmt.Errorf("leaving %s: %w", target.Name, err)
+		}
+
+	case "force":
+		member, err := serfMember(ctx, client, *target)
+		if err != nil {
+			return err
+		}
+		actx.Details["member"] = member.Name
+		if err := client.ForceLeave(ctx, member.Name); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(settle):
+	}
+
+	captureAutopilot(ctx, client, actx.Details, "autopilot_after")
+	return nil
+}
+
+// Rollback restarts or rejoins the server and waits for it to be a healthy voter.
+func (a *ServerLeaveAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	name, ok := actx.State["node"].(string)
+	if !ok {
+		return fmt.Errorf("no server recorded")
+	}
+	mode, _ := actx.State["mode"].(string)
+
+	node, err := actx.Cluster.ServerByName(name)
+	if err != nil {
+		return err
+	}
+
+	if mode == "graceful" {
+		cmd := fmt.Sprintf("sh -c 'rm -f %s && systemctl start nomad'", leaveConfigFile)
+		if _, err := runOnNode(ctx, actx.Driver, *node, cmd); err != nil {
+			return fmt.Errorf("starting nomad on %s: %w", name, err)
+		}
+	}
+
+	client, err := nomadClientExcluding(actx, name)
+	if err != nil {
+		return err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	err = poll(waitCtx, 2*time.Second, func() (bool, error) {
+		if agent, err := actx.Driver.NomadClient(*node); err == nil {
+			_, _ = agent.Join(waitCtx, serfAddrs(actx.Cluster, name)...)
+		}
+
+		health, err := client.AutopilotHealth(waitCtx)
+		if err != nil {
+			return false, err
+		}
+		for _, s := range health.Servers {
+			if strings.Split(s.Address, ":")[0] == node.PrivateIP {
+				if s.Healthy && s.Voter {
+					return true, nil
+				}
+				return false, fmt.Errorf("%s is healthy=%t voter=%t", name, s.Healthy, s.Voter)
+			}
+		}
+		return false, fmt.Errorf("%s not in autopilot health", name)
+	})
+
+	captureAutopilot(ctx, client, actx.Details, "autopilot_after_rollback")
+	if err != nil {
+		return fmt.Errorf("waiting for %s to rejoin: %w", name, err)
+	}
+	return nil
+}
+
+// serfMember finds the gossip member for a node by its private IP.
+func serfMember(ctx context.Context, client *nomad.Client, node driver.Node) (*nomad.AgentMember, error) {
+	members, err := client.Members(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing members: %w", err)
+	}
+	for i := range members {
+		if members[i].Addr == node.PrivateIP {
+			return &members[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%s (%s) is not a gossip member", node.Name, node.PrivateIP)
+}
+
+// captureAutopilot records the autopilot health report under key, or the
+// error that prevented reading it.
+func captureAutopilot(ctx context.Context, client *nomad.Client, details map[string]any, key string) {
+	health, err := client.AutopilotHealth(ctx)
+	if err != nil {
+		details[key] = fmt.Sprintf("error: %v", err)
+		return
+	}
+	details[key] = health
+}
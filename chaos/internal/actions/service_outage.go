@@ -0,0 +1,132 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+)
+
+// faultServices stops, restarts or kills systemd services on each node. In
+// stop mode their active socket units are stopped too, since socket
+// activation would otherwise start the service again on the next client
+// connection. Faulted nodes and units are recorded for restoreServices.
+func faultServices(ctx context.Context, actx *driver.ActionContext, nodes []driver.Node, mode string, services []string) error {
+	actx.State["services"] = services
+	actx.Details["mode"] = mode
+	actx.Details["services"] = services
+
+	units := make(map[string][]string)
+	var faulted []string
+	for _, node := range nodes {
+		client, err := actx.Driver.SSH(ctx, node)
+		if err != nil {
+			return fmt.Errorf("connecting to %s: %w", node.Name, err)
+		}
+
+		switch mode {
+		case "stop":
+			sockets, err := activeSockets(ctx, client, services)
+			if err == nil {
+				units[node.Name] = append(sockets, services...)
+				_, err = runSudo(ctx, client, "systemctl stop "+strings.Join(units[node.Name], " "))
+			}
+		case "restart":
+			_, err = runSudo(ctx, client, "systemctl restart "+strings.Join(services, " "))
+		case "kill":
+			_, err = runSudo(ctx, client, "systemctl kill -s KILL "+strings.Join(services, " "))
+		default:
+			err = fmt.Errorf("unsupported mode %q", mode)
+		}
+		client.Close()
+		if err != nil {
+			return fmt.Errorf("%s %s on %s: %w", mode, strings.Join(services, ","), node.Name, err)
+		}
+
+		faulted = append(faulted, node.Name)
+		actx.State["nodes"] = faulted
+		actx.State["units"] = units
+	}
+	actx.Details["nodes"] = faulted
+	return nil
+}
+
+// restoreServices starts the units recorded by faultServices and waits for
+// the services to be active.
+func restoreServices(ctx context.Context, actx *driver.ActionContext) error {
+	nodes, err := nodesFromState(actx, "nodes")
+	if err != nil {
+		return err
+	}
+	services, _ := actx.State["services"].([]string)
+	units, _ := actx.State["units"].(map[string][]string)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	var errs []error
+	for _, node := range nodes {
+		start := services
+		if u := units[node.Name]; len(u) > 0 {
+			start = u
+		}
+		if _, err := runOnNode(ctx, actx.Driver, node, "systemctl start "+strings.Join(start, " ")); err != nil {
+			errs = append(errs, fmt.Errorf("starting services on %s: %w", node.Name, err))
+			continue
+		}
+		if err := waitForServices(waitCtx, actx.Driver, node, services); err != nil {
+			errs = append(errs, fmt.Errorf("waiting for services on %s: %w", node.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// holdOutage keeps a fault in place for duration and then restores it, so
+// assertions later in the scenario observe the recovery. A zero duration
+// leaves the fault until rollback.
+func holdOutage(ctx context.Context, actx *driver.ActionContext, duration time.Duration, restore func() error) error {
+	if duration <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(duration):
+	}
+
+	start := time.Now()
+	if err := restore(); err != nil {
+		return err
+	}
+	actx.Details["outage"] = duration.String()
+	actx.Details["time_to_recover"] = time.Since(start).Round(time.Millisecond).String()
+	return nil
+}
+
+// activeSockets lists the active socket units that activate the services,
+// e.g. libvirtd.socket, libvirtd-ro.socket and libvirtd-admin.socket.
+func activeSockets(ctx context.Context, client driver.SSHClient, services []string) ([]string, error) {
+	out, err := runSudo(ctx, client, "systemctl list-units --type=socket --state=active --plain --no-legend")
+	if err != nil {
+		return nil, fmt.Errorf("listing sockets: %w", err)
+	}
+
+	var sockets []string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		unit := fields[0]
+		for _, svc := range services {
+			if unit == svc+".socket" || unit == svc+"-ro.socket" || unit == svc+"-admin.socket" {
+				sockets = append(sockets, unit)
+			}
+		}
+	}
+	return sockets, nil
+}
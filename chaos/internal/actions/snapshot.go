@@ -0,0 +1,279 @@
+package actions
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// snapshotPathKey is the State key under which snapshot records its file,
+// so restore-snapshot can find it through the action history.
+const snapshotPathKey = "snapshot_path"
+
+// SnapshotAction saves a raft snapshot to a local file.
+type SnapshotAction struct{}
+
+// Name returns the action identifier.
+func (a *SnapshotAction) Name() string {
+	return "snapshot"
+}
+
+// Description returns a human-readable description.
+func (a *SnapshotAction) Description() string {
+	return "Save a Nomad raft snapshot to a local file stored with the run"
+}
+
+// Execute downloads the snapshot and verifies it is a gzip archive.
+func (a *SnapshotAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	dir := params.String(args, "dir", filepath.Join(actx.ArtifactDir, "snapshots"))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating snapshot directory: %w", err)
+	}
+
+	leader, err := actx.Driver.GetNomadLeader(ctx, actx.Cluster)
+	if err != nil {
+		return fmt.Errorf("finding leader: %w", err)
+	}
+	client, err := actx.Driver.NomadClient(*leader)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.snap", actx.Cluster.Name, time.Now().UTC().Format("20060102T150405Z"))
+	file := filepath.Join(dir, name)
+
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", file, err)
+	}
+
+	hash := sha256.New()
+	size, err := client.SnapshotSave(ctx, io.MultiWriter(f, hash))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = checkSnapshot(file)
+	}
+	if err != nil {
+		os.Remove(file)
+		return err
+	}
+
+	actx.State[snapshotPathKey] = file
+	actx.Details["file"] = file
+	actx.Details["size_bytes"] = size
+	actx.Details["sha256"] = hex.EncodeToString(hash.Sum(nil))
+	actx.Details["source"] = leader.Name
+	return nil
+}
+
+// Rollback is a no-op; the snapshot file is kept with the run.
+func (a *SnapshotAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	return nil
+}
+
+// checkSnapshot verifies a snapshot is a non-empty gzip archive.
+func checkSnapshot(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	magic := make([]byte, 2)
+	if _, err := io.ReadFull(bufio.NewReader(f), magic); err != nil {
+		return fmt.Errorf("snapshot %s is empty or truncated", file)
+	}
+	if magic[0] != 0x1f || magic[1] != 0x8b {
+		return fmt.Errorf("snapshot %s is not a gzip archive", file)
+	}
+	return nil
+}
+
+// WipeRaftDataAction wipes or corrupts the raft data directory on servers.
+type WipeRaftDataAction struct{}
+
+// Name returns the action identifier.
+func (a *WipeRaftDataAction) Name() string {
+	return "wipe-raft-data"
+}
+
+// Description returns a human-readable description.
+func (a *WipeRaftDataAction) Description() string {
+	return "Stop Nomad and wipe or corrupt the raft data directory on selected servers"
+}
+
+// Execute moves the raft directory aside (wipe) or garbles raft.db (corrupt).
+// The original directory is always preserved so rollback is exact.
+func (a *WipeRaftDataAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	mode := params.String(args, "mode", "wipe")
+	if mode != "wipe" && mode != "corrupt" {
+		return fmt.Errorf("invalid mode %q: must be wipe or corrupt", mode)
+	}
+	restart := params.Bool(args, "restart", true)
+	raftDir := path.Join(params.String(args, "data_dir", defaultDataDir), "server", "raft")
+
+	selectors := params.StringSlice(args, "nodes")
+	if len(selectors) == 0 {
+		return fmt.Errorf("nodes is required")
+	}
+	nodes, err := resolveNodes(ctx, actx, selectors)
+	if err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		if n.Role != driver.RoleServer {
+			return fmt.Errorf("%s is not a server", n.Name)
+		}
+	}
+
+	backup := fmt.Sprintf("%s.chaos-%d", raftDir, time.Now().Unix())
+	actx.State["raft_dir"] = raftDir
+	actx.State["backup_dir"] = backup
+	actx.Details["mode"] = mode
+	actx.Details["raft_dir"] = raftDir
+	actx.Details["backup_dir"] = backup
+
+	var script string
+	switch mode {
+	case "wipe":
+		script = fmt.Sprintf("systemctl stop nomad && mv %[1]s %[2]s && install -d -o nomad -g nomad -m 0700 %[1]s", raftDir, backup)
+	case "corrupt":
+		// Copy under a temporary name so a partial copy is never taken for
+		// the backup on rollback
+		script = fmt.Sprintf("systemctl stop nomad && cp -a %[1]s %[2]s.tmp && mv %[2]s.tmp %[2]s && dd if=/dev/urandom of=%[1]s/raft.db bs=4096 seek=1 count=16 conv=notrunc status=none", raftDir, backup)
+	}
+	if restart {
+		script += " && systemctl start nomad"
+	}
+	cmd := fmt.Sprintf("sh -c '%s'", script)
+
+	// Record each node before touching it: a script that fails partway may
+	// already have stopped Nomad or moved the raft directory
+	var wiped []string
+	for _, node := range nodes {
+		wiped = append(wiped, node.Name)
+		actx.State["nodes"] = wiped
+		actx.Details["nodes"] = wiped
+		if _, err := runOnNode(ctx, actx.Driver, node, cmd); err != nil {
+			return fmt.Errorf("%s raft data on %s: %w", mode, node.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Rollback puts the original raft directory back and restarts Nomad. A node
+// whose backup was never made keeps its raft directory and is only started.
+func (a *WipeRaftDataAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	nodes, err := nodesFromState(actx, "nodes")
+	if err != nil {
+		return err
+	}
+	raftDir, _ := actx.State["raft_dir"].(string)
+	backup, _ := actx.State["backup_dir"].(string)
+
+	cmd := fmt.Sprintf("sh -c 'systemctl stop nomad && if [ -d %[2]s ]; then rm -rf %[1]s && mv %[2]s %[1]s; fi && systemctl start nomad'", raftDir, backup)
+
+	var errs []error
+	for _, node := range nodes {
+		if _, err := runOnNode(ctx, actx.Driver, node, cmd); err != nil {
+			errs = append(errs, fmt.Errorf("restoring raft data on %s: %w", node.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RestoreSnapshotAction restores a raft snapshot into the cluster.
+type RestoreSnapshotAction struct{}
+
+// Name returns the action identifier.
+func (a *RestoreSnapshotAction) Name() string {
+	return "restore-snapshot"
+}
+
+// Description returns a human-readable description.
+func (a *RestoreSnapshotAction) Description() string {
+	return "Restore a Nomad raft snapshot (default: the latest one taken in this run)"
+}
+
+// Execute uploads the snapshot and waits for a leader to be available again.
+func (a *RestoreSnapshotAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	timeout, err := params.Duration(args, "timeout", time.Minute)
+	if err != nil {
+		return err
+	}
+
+	file := params.String(args, "file", "")
+	if file == "" {
+		v, ok := driver.LookupState(actx.History, snapshotPathKey)
+		if !ok {
+			return fmt.Errorf("no file given and no snapshot taken earlier in this run")
+		}
+		if file, ok = v.(string); !ok || file == "" {
+			return fmt.Errorf("snapshot path recorded earlier in this run is %v, not a file name", v)
+		}
+	}
+	if err := checkSnapshot(file); err != nil {
+		return err
+	}
+	actx.Details["file"] = file
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// A freshly wiped cluster may still be electing; wait for a leader first
+	var leader *driver.Node
+	err = poll(waitCtx, 2*time.Second, func() (bool, error) {
+		leader, err = actx.Driver.GetNomadLeader(waitCtx, actx.Cluster)
+		return err == nil, err
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for leader before restore: %w", err)
+	}
+
+	client, err := actx.Driver.NomadClient(*leader)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	start := time.Now()
+	if err := client.SnapshotRestore(waitCtx, f); err != nil {
+		return err
+	}
+
+	err = poll(waitCtx, time.Second, func() (bool, error) {
+		_, err := actx.Driver.GetNomadLeader(waitCtx, actx.Cluster)
+		return err == nil, err
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for leader after restore: %w", err)
+	}
+
+	actx.Details["restored_via"] = leader.Name
+	actx.Details["restore_time"] = time.Since(start).Round(time.Millisecond).String()
+	return nil
+}
+
+// Rollback is a no-op; a restore cannot be undone.
+func (a *RestoreSnapshotAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	return nil
+}
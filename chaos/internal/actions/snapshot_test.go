@@ -0,0 +1,49 @@
+package actions
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+)
+
+func TestRestoreSnapshotRejectsBadRecordedPath(t *testing.T) {
+	for _, v := range []any{42, ""} {
+		actx := newFakeContext(&fakeSSH{})
+		actx.History = []*driver.ActionContext{{State: map[string]any{snapshotPathKey: v}}}
+		if err := (&RestoreSnapshotAction{}).Execute(context.Background(), actx, map[string]any{}); err == nil {
+			t.Errorf("Execute with recorded path %#v succeeded", v)
+		}
+	}
+}
+
+func TestWipeRaftDataRollsBackPartialFailure(t *testing.T) {
+	first := &fakeSSH{}
+	second := &fakeSSH{fail: map[string]bool{"sh -c 'systemctl stop nomad && mv": true}}
+	actx := newFakeContext(first)
+	actx.Driver.(*fakeDriver).nodes = map[string]*fakeSSH{"server-1": second}
+	a := &WipeRaftDataAction{}
+
+	if err := a.Execute(context.Background(), actx, map[string]any{"nodes": "server-0,server-1"}); err == nil {
+		t.Fatal("Execute succeeded although server-1 failed")
+	}
+	if got := actx.State["nodes"]; !slices.Equal(got.([]string), []string{"server-0", "server-1"}) {
+		t.Errorf("State nodes = %v, want both servers", got)
+	}
+
+	first.cmds, second.cmds = nil, nil
+	if err := a.Rollback(context.Background(), actx); err != nil {
+		t.Fatal(err)
+	}
+	for name, ssh := range map[string]*fakeSSH{"server-0": first, "server-1": second} {
+		if len(ssh.cmds) != 1 {
+			t.Fatalf("%s ran %q, want one restore", name, ssh.cmds)
+		}
+		backup := actx.State["backup_dir"].(string)
+		if cmd := ssh.cmds[0]; !strings.Contains(cmd, "if [ -d "+backup+" ]; then rm -rf") {
+			t.Errorf("%s restore %q removes the raft directory without checking for the backup", name, cmd)
+		}
+	}
+}
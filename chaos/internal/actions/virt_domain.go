@@ -0,0 +1,130 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+	"github.com/libvirt-standalone/chaos/internal/virt"
+)
+
+// VirtDomainAction faults the libvirt domain behind a nomad-driver-virt task.
+type VirtDomainAction struct{}
+
+// Name returns the action identifier.
+func (a *VirtDomainAction) Name() string {
+	return "virt-domain"
+}
+
+// Description returns a human-readable description.
+func (a *VirtDomainAction) Description() string {
+	return "Destroy, suspend or kill the qemu process of the libvirt domain behind a virt task"
+}
+
+// Execute finds each selected allocation's running domain on its client and
+// faults it behind the driver's back. With task set only that task's domain
+// is faulted.
+func (a *VirtDomainAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	mode := params.String(args, "mode", "destroy")
+	var op string
+	switch mode {
+	case "destroy":
+		op = virt.Virsh + " destroy %[1]s"
+	case "suspend":
+		op = virt.Virsh + " suspend %[1]s"
+	case "kill-qemu":
+		op = "sh -c 'kill -9 $(cat " + virt.PIDFile("%[1]s") + ")'"
+	default:
+		return fmt.Errorf("invalid mode %q: must be destroy, suspend or kill-qemu", mode)
+	}
+
+	client, allocs, err := selectAllocs(ctx, actx, args)
+	if err != nil {
+		return err
+	}
+	actx.State["mode"] = mode
+	actx.Details["mode"] = mode
+	task := params.String(args, "task", "")
+
+	// node name -> faulted domains, for rollback
+	faulted := make(map[string][]string)
+	actx.State["domains"] = faulted
+	domains := make(map[string]virt.Domain)
+	actx.Details["domains"] = domains
+
+	return forEachAlloc(actx, allocs, func(alloc nomad.Allocation) error {
+		node, err := allocHost(ctx, actx, client, alloc)
+		if err != nil {
+			return err
+		}
+		ssh, err := actx.Driver.SSH(ctx, *node)
+		if err != nil {
+			return fmt.Errorf("connecting to %s: %w", node.Name, err)
+		}
+		defer ssh.Close()
+
+		found, err := virt.AllocDomains(ctx, ssh, alloc.ID)
+		if err != nil {
+			return fmt.Errorf("finding domain on %s: %w", node.Name, err)
+		}
+		if task != "" {
+			// Job listings return stubs without the job's task drivers
+			full := &alloc
+			if full.Job == nil {
+				if full, err = client.Allocation(ctx, alloc.ID); err != nil {
+					return fmt.Errorf("reading allocation %s: %w", alloc.ID, err)
+				}
+			}
+			found = virt.TaskDomains(found, task, len(virt.VirtTasks(full.TaskDrivers())))
+		}
+		var domain *virt.Domain
+		for i := range found {
+			if found[i].Running() {
+				domain = &found[i]
+				break
+			}
+		}
+		if domain == nil && task != "" {
+			return fmt.Errorf("no running domain for task %s on %s", task, node.Name)
+		}
+		if domain == nil {
+			return fmt.Errorf("no running domain on %s", node.Name)
+		}
+
+		if _, err := runSudo(ctx, ssh, fmt.Sprintf(op, domain.Name)); err != nil {
+			return fmt.Errorf("%s %s on %s: %w", mode, domain.Name, node.Name, err)
+		}
+		faulted[node.Name] = append(faulted[node.Name], domain.Name)
+		domains[alloc.ID] = *domain
+		return nil
+	})
+}
+
+// Rollback resumes suspended domains. Destroyed or killed domains are left
+// to the driver and the restart policy.
+func (a *VirtDomainAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	if mode, _ := actx.State["mode"].(string); mode != "suspend" {
+		return nil
+	}
+	faulted, _ := actx.State["domains"].(map[string][]string)
+
+	var errs []error
+	for name, domains := range faulted {
+		node, err := actx.Cluster.NodeByName(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, d := range domains {
+			// The domain may have been destroyed by the driver in the meantime
+			cmd := fmt.Sprintf(`sh -c '%[1]s domstate %[2]s | grep -q paused || exit 0; %[1]s resume %[2]s'`, virt.Virsh, d)
+			if _, err := runOnNode(ctx, actx.Driver, *node, cmd); err != nil {
+				errs = append(errs, fmt.Errorf("resuming %s on %s: %w", d, name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
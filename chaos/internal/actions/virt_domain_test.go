@@ -0,0 +1,66 @@
+package actions
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/httpapi"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+)
+
+func TestVirtDomainFaultsSelectedTask(t *testing.T) {
+	const job = `{"TaskGroups": [{"Name": "vms", "Tasks": [
+		{"Name": "web", "Driver": "nomad-driver-virt"},
+		{"Name": "db", "Driver": "nomad-driver-virt"}
+	]}]}`
+	responses := map[string]string{
+		"/v1/job/vms/allocations": `[{"ID": "1a2b3c4d-0000", "NodeID": "n1", "TaskGroup": "vms",
+			"DesiredStatus": "run", "ClientStatus": "running", "TaskStates": {"web": {}, "db": {}}}]`,
+		"/v1/allocation/1a2b3c4d-0000": `{"ID": "1a2b3c4d-0000", "NodeID": "n1", "TaskGroup": "vms", "Job": ` + job + `}`,
+		"/v1/node/n1":                  `{"ID": "n1", "Attributes": {"unique.network.ip-address": "10.0.1.5"}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	client, err := nomad.NewClient(httpapi.Config{Address: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The db domain is listed first, so picking the first running domain
+	// of the allocation would fault the wrong task
+	ssh := &fakeSSH{outputs: map[string]string{
+		"sh -c 'for d in": "db-1a2b3c4d u1 running\nweb-1a2b3c4d u2 running\n",
+	}}
+	actx := newFakeContext(ssh)
+	actx.Driver.(*fakeDriver).nomad = client
+	actx.Cluster.Clients = []driver.Node{{Name: "client-0", Role: driver.RoleClient, PrivateIP: "10.0.1.5"}}
+
+	err = (&VirtDomainAction{}).Execute(context.Background(), actx, map[string]any{
+		"job":  "vms",
+		"task": "web",
+		"mode": "suspend",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := actx.State["domains"].(map[string][]string)["client-0"]; !slices.Equal(got, []string{"web-1a2b3c4d"}) {
+		t.Errorf("faulted domains = %v, want [web-1a2b3c4d]", got)
+	}
+	for _, cmd := range ssh.cmds {
+		if strings.Contains(cmd, "suspend db-") {
+			t.Errorf("suspended the db task's domain: %q", cmd)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// defaultVirtImagePath matches nomad_virt_image_paths in the ansible
+// clients group vars, where the driver writes its thin copies.
+const defaultVirtImagePath = "/var/local/statics/images/"
+
+// VirtImageStorageAction fills or throttles the filesystem behind the virt
+// driver's image path on clients.
+type VirtImageStorageAction struct{}
+
+// Name returns the action identifier.
+func (a *VirtImageStorageAction) Name() string {
+	return "virt-image-storage"
+}
+
+// Description returns a human-readable description.
+func (a *VirtImageStorageAction) Description() string {
+	return "Fill or throttle the filesystem behind the virt driver image path on clients, recording running VMs first"
+}
+
+// Execute records the running virt domains, then applies disk-fill or
+// io-throttle to the image path.
+func (a *VirtImageStorageAction) Execute(ctx context.Context, actx *driver.ActionContext, args map[string]any) error {
+	mode := params.String(args, "mode", "fill")
+	inner, err := virtStorageFault(mode)
+	if err != nil {
+		return err
+	}
+
+	innerArgs := maps.Clone(args)
+	if innerArgs == nil {
+		innerArgs = make(map[string]any)
+	}
+	innerArgs["path"] = params.String(args, "path", defaultVirtImagePath)
+	if len(params.StringSlice(args, "nodes")) == 0 {
+		innerArgs["nodes"] = "clients"
+	}
+
+	nodes, err := diskTargets(ctx, actx, innerArgs)
+	if err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		if n.Role != driver.RoleClient {
+			return fmt.Errorf("%s is not a client", n.Name)
+		}
+	}
+
+	baseline, err := recordVirtDomains(ctx, actx, nodes)
+	if err != nil {
+		return err
+	}
+	actx.State[driver.StateVirtDomains] = baseline
+	actx.State[driver.StateFaultTime] = time.Now()
+	actx.State["mode"] = mode
+	actx.Details["mode"] = mode
+	actx.Details["domains_before"] = baseline
+
+	return inner.Execute(ctx, actx, innerArgs)
+}
+
+// Rollback undoes the fill or throttle.
+func (a *VirtImageStorageAction) Rollback(ctx context.Context, actx *driver.ActionContext) error {
+	mode, _ := actx.State["mode"].(string)
+	inner, err := virtStorageFault(mode)
+	if err != nil {
+		return err
+	}
+	return inner.Rollback(ctx, actx)
+}
+
+// virtStorageFault returns the disk action implementing a mode.
+func virtStorageFault(mode string) (Action, error) {
+	switch mode {
+	case "fill":
+		return &DiskFillAction{}, nil
+	case "throttle":
+		return &IOThrottleAction{}, nil
+	default:
+		return nil, fmt.Errorf("invalid mode %q: must be fill or throttle", mode)
+	}
+}
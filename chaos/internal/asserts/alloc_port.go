@@ -0,0 +1,121 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// AllocPortReachableAssertion checks that a faulted allocation's service
+// port answers again, following replacements if it was rescheduled.
+type AllocPortReachableAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *AllocPortReachableAssertion) Name() string {
+	return "alloc-port-reachable"
+}
+
+// Description returns a human-readable description.
+func (a *AllocPortReachableAssertion) Description() string {
+	return "Verify that the allocations' port (default: http) accepts connections again within the timeout"
+}
+
+// Check probes the port from the client node running each allocation, since
+// dynamic host ports are usually not reachable from outside the VPC. With a
+// path it makes an HTTP request and requires a non-error status.
+func (a *AllocPortReachableAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	timeout, err := params.Duration(args, "within", 2*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	label := params.String(args, "port", "http")
+	path := params.String(args, "path", "")
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+	result.Details["port"] = label
+
+	allocIDs, _, err := targetAllocs(actx, args)
+	if err != nil {
+		result.Message = err.Error()
+		return result, nil
+	}
+
+	var endpoints, failing map[string]string
+	ok, err := eventually(ctx, result, timeout, 3*time.Second, func() bool {
+		endpoints = make(map[string]string)
+		failing = make(map[string]string)
+
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			result.Details["error"] = err.Error()
+			return false
+		}
+		for _, id := range allocIDs {
+			endpoint, err := a.probe(ctx, actx, client, id, label, path)
+			if endpoint != "" {
+				endpoints[id] = endpoint
+			}
+			if err != nil {
+				failing[id] = err.Error()
+			}
+		}
+		return len(failing) == 0
+	})
+	result.Details["endpoints"] = endpoints
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	if !ok {
+		result.Details["failing"] = failing
+		result.Message = fmt.Sprintf("%d/%d allocations not reachable on %s within %s", len(failing), len(allocIDs), label, timeout)
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("All %d allocations reachable on %s", len(allocIDs), label)
+	return result, nil
+}
+
+// probe checks the latest allocation in id's chain and returns the
+// endpoint it tried.
+func (a *AllocPortReachableAssertion) probe(ctx context.Context, actx *driver.AssertContext, client *nomad.Client, id, label, path string) (string, error) {
+	chain, err := allocChain(ctx, client, id)
+	if len(chain) == 0 {
+		return "", err
+	}
+	alloc := chain[len(chain)-1]
+	if alloc.ClientStatus != nomad.AllocClientStatusRunning {
+		return "", fmt.Errorf("allocation %s is %s", alloc.ID, alloc.ClientStatus)
+	}
+
+	port, ok := alloc.Port(label)
+	if !ok {
+		return "", fmt.Errorf("allocation %s has no %q port", alloc.ID, label)
+	}
+	node, err := allocNode(ctx, actx, client, alloc)
+	if err != nil {
+		return "", err
+	}
+	host := port.HostIP
+	if host == "" {
+		host = node.PrivateIP
+	}
+	endpoint := fmt.Sprintf("%s:%d", host, port.Value)
+
+	cmd := fmt.Sprintf(`timeout 5 bash -c "</dev/tcp/%s/%d"`, host, port.Value)
+	if path != "" {
+		endpoint = "http://" + endpoint + path
+		cmd = fmt.Sprintf("curl -fsS -o /dev/null -m 5 %s", endpoint)
+	}
+	if _, err := runOnNode(ctx, actx, *node, cmd); err != nil {
+		return endpoint, fmt.Errorf("%s on %s: %w", endpoint, node.Name, err)
+	}
+	return endpoint, nil
+}
@@ -0,0 +1,176 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// AllocsMigratedAssertion checks that allocations moved off a drained node
+// were replaced by healthy allocations on other nodes.
+type AllocsMigratedAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *AllocsMigratedAssertion) Name() string {
+	return "allocs-migrated"
+}
+
+// Description returns a human-readable description.
+func (a *AllocsMigratedAssertion) Description() string {
+	return "Verify that allocations from a drained node are healthy on other nodes within the timeout"
+}
+
+// Check follows each recorded allocation to its latest replacement, polling
+// until every replacement is healthy on a different node or the timeout
+// expires.
+func (a *AllocsMigratedAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	timeout, err := params.Duration(args, "within", 2*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	pollInterval, err := params.Duration(args, "poll", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+
+	// Allocations come from an earlier drain-node step unless given explicitly
+	allocIDs := params.StringSlice(args, "allocs")
+	drainedNode := params.String(args, "node_id", "")
+	if len(allocIDs) == 0 {
+		var ok bool
+		if allocIDs, drainedNode, ok = drainedAllocs(actx.History); !ok {
+			result.Message = "No drained allocations recorded earlier in this run"
+			return result, nil
+		}
+	}
+	result.Details["allocs"] = allocIDs
+
+	if len(allocIDs) == 0 {
+		result.Success = true
+		result.Message = "No allocations needed to migrate"
+		return result, nil
+	}
+
+	originals := make(map[string]*nomad.Allocation)
+	var replacements, pending map[string]string
+	ok, err := eventually(ctx, result, timeout, pollInterval, func() bool {
+		replacements, pending = a.findReplacements(ctx, actx, allocIDs, drainedNode, originals)
+		return len(pending) == 0
+	})
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	result.Details["replacements"] = replacements
+	if !ok {
+		result.Details["pending"] = pending
+		result.Message = fmt.Sprintf("%d/%d allocations not healthy elsewhere within %s", len(pending), len(allocIDs), timeout)
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("All %d allocations healthy on other nodes", len(allocIDs))
+	return result, nil
+}
+
+// findReplacements maps each original allocation to "newID@node" once its
+// replacement is healthy, and reports why the rest are still pending.
+// Originals are cached across polls since only their job is needed.
+func (a *AllocsMigratedAssertion) findReplacements(ctx context.Context, actx *driver.AssertContext, allocIDs []string, drainedNode string, originals map[string]*nomad.Allocation) (map[string]string, map[string]string) {
+	replacements := make(map[string]string)
+	pending := make(map[string]string)
+
+	client, err := leaderClient(ctx, actx)
+	if err != nil {
+		for _, id := range allocIDs {
+			pending[id] = err.Error()
+		}
+		return replacements, pending
+	}
+
+	jobAllocs := make(map[string][]nomad.Allocation)
+	for _, id := range allocIDs {
+		orig, ok := originals[id]
+		if !ok {
+			orig, err = client.Allocation(ctx, id)
+			if err != nil {
+				pending[id] = err.Error()
+				continue
+			}
+			originals[id] = orig
+		}
+
+		key := orig.Namespace + "/" + orig.JobID
+		allocs, ok := jobAllocs[key]
+		if !ok {
+			allocs, err = client.JobAllocations(ctx, orig.JobID, orig.Namespace)
+			if err != nil {
+				pending[id] = err.Error()
+				continue
+			}
+			jobAllocs[key] = allocs
+		}
+
+		if replacement, reason := migrationStatus(id, allocs, drainedNode); reason != "" {
+			pending[id] = reason
+		} else {
+			replacements[id] = replacement
+		}
+	}
+	return replacements, pending
+}
+
+// drainedAllocs returns the allocation IDs recorded by the latest action
+// that recorded any, with the Nomad node ID that same action recorded ("" if
+// none), so a later alloc-stop never pairs with an older drain's node.
+func drainedAllocs(history []*driver.ActionContext) ([]string, string, bool) {
+	for i := len(history) - 1; i >= 0; i-- {
+		v, ok := history[i].State[driver.StateAllocIDs]
+		if !ok {
+			continue
+		}
+		ids, _ := v.([]string)
+		nodeID, _ := history[i].State[driver.StateNomadNodeID].(string)
+		return ids, nodeID, true
+	}
+	return nil, "", false
+}
+
+// migrationStatus follows the replacement chain of allocation id through
+// allocs, its job's allocations, to the latest replacement, so one that
+// failed and was rescheduled again is judged by its successor. It returns
+// "newID@node" once that replacement is healthy off the drained node, or
+// why it is still pending.
+func migrationStatus(id string, allocs []nomad.Allocation, drainedNode string) (string, string) {
+	byID := make(map[string]*nomad.Allocation)
+	next := make(map[string]string)
+	for i := range allocs {
+		byID[allocs[i].ID] = &allocs[i]
+		if prev := allocs[i].PreviousAllocation; prev != "" {
+			next[prev] = allocs[i].ID
+		}
+	}
+
+	latest := id
+	for n := 0; next[latest] != "" && n < 20; n++ {
+		latest = next[latest]
+	}
+	alloc := byID[latest]
+	switch {
+	case latest == id || alloc == nil:
+		return "", "no replacement placed"
+	case alloc.NodeID == drainedNode:
+		return "", fmt.Sprintf("replacement %s placed on the drained node", alloc.ID)
+	case !alloc.IsHealthy():
+		return "", fmt.Sprintf("replacement %s on %s is %s", alloc.ID, alloc.NodeName, alloc.ClientStatus)
+	}
+	return alloc.ID + "@" + alloc.NodeName, ""
+}
@@ -0,0 +1,70 @@
+package asserts
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+)
+
+func TestMigrationStatus(t *testing.T) {
+	data := `[
+		{"ID": "orig", "NodeID": "drained", "ClientStatus": "complete"},
+		{"ID": "first", "PreviousAllocation": "orig", "NodeID": "n2", "NodeName": "client-1", "ClientStatus": "failed"},
+		{"ID": "second", "PreviousAllocation": "first", "NodeID": "n3", "NodeName": "client-2", "ClientStatus": "running"},
+		{"ID": "lonely", "NodeID": "drained", "ClientStatus": "running"},
+		{"ID": "back", "NodeID": "drained", "ClientStatus": "complete"},
+		{"ID": "back-2", "PreviousAllocation": "back", "NodeID": "drained", "NodeName": "client-0", "ClientStatus": "running"},
+		{"ID": "slow", "NodeID": "drained", "ClientStatus": "complete"},
+		{"ID": "slow-2", "PreviousAllocation": "slow", "NodeID": "n2", "NodeName": "client-1", "ClientStatus": "pending"}
+	]`
+	var allocs []nomad.Allocation
+	if err := json.Unmarshal([]byte(data), &allocs); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id          string
+		replacement string
+		reason      string
+	}{
+		{"orig", "second@client-2", ""},
+		{"lonely", "", "no replacement placed"},
+		{"back", "", "replacement back-2 placed on the drained node"},
+		{"slow", "", "replacement slow-2 on client-1 is pending"},
+	}
+	for _, tt := range tests {
+		replacement, reason := migrationStatus(tt.id, allocs, "drained")
+		if replacement != tt.replacement || reason != tt.reason {
+			t.Errorf("migrationStatus(%s) = %q, %q, want %q, %q", tt.id, replacement, reason, tt.replacement, tt.reason)
+		}
+	}
+}
+
+func TestDrainedAllocs(t *testing.T) {
+	drain := &driver.ActionContext{State: map[string]any{
+		driver.StateAllocIDs:    []string{"a1"},
+		driver.StateNomadNodeID: "node-1",
+	}}
+	stop := &driver.ActionContext{State: map[string]any{
+		driver.StateAllocIDs: []string{"a2"},
+	}}
+	other := &driver.ActionContext{State: map[string]any{"nodes": []string{"server-0"}}}
+
+	ids, nodeID, ok := drainedAllocs([]*driver.ActionContext{drain, other})
+	if !ok || !slices.Equal(ids, []string{"a1"}) || nodeID != "node-1" {
+		t.Errorf("after a drain = %v, %q, %v", ids, nodeID, ok)
+	}
+
+	// A later alloc-stop must not inherit the earlier drain's node
+	ids, nodeID, ok = drainedAllocs([]*driver.ActionContext{drain, stop, other})
+	if !ok || !slices.Equal(ids, []string{"a2"}) || nodeID != "" {
+		t.Errorf("after an alloc-stop = %v, %q, %v", ids, nodeID, ok)
+	}
+
+	if _, _, ok := drainedAllocs([]*driver.ActionContext{other}); ok {
+		t.Error("found allocations in a history without any")
+	}
+}
@@ -0,0 +1,231 @@
+package asserts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/consul"
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/httpapi"
+	"github.com/libvirt-standalone/chaos/internal/jsonpath"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+	"github.com/libvirt-standalone/chaos/internal/vault"
+)
+
+// APIAssertion checks the response of a Nomad, Consul or Vault endpoint or
+// an arbitrary URL against JSONPath expressions.
+type APIAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *APIAssertion) Name() string {
+	return "api"
+}
+
+// Description returns a human-readable description.
+func (a *APIAssertion) Description() string {
+	return "Verify a Nomad, Consul, Vault or HTTP endpoint's status and JSON response against expressions"
+}
+
+// Check sends the request and evaluates every expect expression against
+// the decoded JSON body, e.g. "len($.Members[?(@.Status=='alive')]) >= 3".
+// The status must be in status (default any 2xx). Nomad requests go to the
+// leader and Consul and Vault requests to the first server unless node
+// names another; all use the configured token and TLS settings. With
+// within set the request is retried until the checks pass.
+func (a *APIAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	rawURL := params.String(args, "url", "")
+	api := params.String(args, "api", "nomad")
+	if rawURL != "" {
+		api = "url"
+	}
+	path := params.String(args, "path", "")
+	if api != "url" && path == "" {
+		return nil, fmt.Errorf("path is required (or url for an arbitrary endpoint)")
+	}
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	method := strings.ToUpper(params.String(args, "method", http.MethodGet))
+	body := params.String(args, "body", "")
+
+	timeout, err := params.Duration(args, "within", 0)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := params.Duration(args, "interval", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []int
+	for _, s := range params.StringSlice(args, "status") {
+		code, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status %q", s)
+		}
+		statuses = append(statuses, code)
+	}
+	var exprs []*jsonpath.Expr
+	for _, src := range rawList(args, "expect") {
+		expr, err := jsonpath.Compile(src)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	var base string
+	switch api {
+	case "nomad", "consul", "vault":
+	case "url":
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid url %q", rawURL)
+		}
+		base, path = u.Scheme+"://"+u.Host, u.RequestURI()
+	default:
+		return nil, fmt.Errorf("invalid api %q: must be nomad, consul, vault or url", api)
+	}
+
+	result := NewResult(a.Name(), false, "")
+	if timeout > 0 {
+		result.Details["timeout"] = timeout.String()
+	}
+
+	var failures []string
+	ok, err := eventually(ctx, result, timeout, interval, func() bool {
+		failures = nil
+		delete(result.Details, "body")
+
+		// The target is resolved every attempt, so a request to the
+		// leader follows an election
+		client, target, err := apiClient(ctx, actx, api, base, args)
+		if err != nil {
+			failures = []string{err.Error()}
+			return false
+		}
+		result.Details["request"] = fmt.Sprintf("%s %s%s", method, client.Address(), path)
+		if target != "" {
+			result.Details["node"] = target
+		}
+
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+		resp, err := client.Request(ctx, method, path, reader)
+		if err != nil {
+			failures = []string{err.Error()}
+			return false
+		}
+		result.Details["status"] = resp.StatusCode
+
+		if len(statuses) == 0 && (resp.StatusCode < 200 || resp.StatusCode > 299) || len(statuses) > 0 && !slices.Contains(statuses, resp.StatusCode) {
+			failures = append(failures, fmt.Sprintf("status %d", resp.StatusCode))
+		}
+		if len(exprs) > 0 {
+			var doc any
+			if err := json.Unmarshal(resp.Body, &doc); err != nil {
+				failures = append(failures, fmt.Sprintf("response is not JSON: %v", err))
+			} else {
+				for _, expr := range exprs {
+					if !expr.Test(doc) {
+						failures = append(failures, fmt.Sprintf("%s is false", expr))
+					}
+				}
+			}
+		}
+		if len(failures) > 0 {
+			result.Details["body"] = truncate(strings.TrimSpace(string(resp.Body)), maxOutputDetail)
+		}
+		return len(failures) == 0
+	})
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	if !ok {
+		result.Details["failures"] = failures
+		result.Message = fmt.Sprintf("%s %s failed: %s", method, path, strings.Join(failures, "; "))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%s %s returned %v with %d checks passing", method, path, result.Details["status"], len(exprs))
+	return result, nil
+}
+
+// apiClient returns the client for the api arg and the node it talks to.
+// An arbitrary URL is reached directly at base.
+func apiClient(ctx context.Context, actx *driver.AssertContext, api, base string, args map[string]any) (*httpapi.Client, string, error) {
+	if api == "url" {
+		client, err := httpapi.NewClient(httpapi.Config{
+			Address:  base,
+			Insecure: params.Bool(args, "insecure", false),
+		})
+		return client, "", err
+	}
+
+	selector := params.String(args, "node", "")
+	switch api {
+	case "nomad":
+		if selector == "" {
+			selector = "leader"
+		}
+	case "consul", "vault":
+		if selector == "" {
+			selector = "servers"
+		}
+	}
+
+	nodes, err := driver.SelectNodes(ctx, actx.Driver, actx.Cluster, []string{selector})
+	if err != nil {
+		return nil, "", err
+	}
+	node := nodes[0]
+
+	var client *httpapi.Client
+	switch api {
+	case "nomad":
+		var c *nomad.Client
+		if c, err = actx.Driver.NomadClient(node); err == nil {
+			client = c.Client
+		}
+	case "consul":
+		var c *consul.Client
+		if c, err = actx.Driver.ConsulClient(node); err == nil {
+			client = c.Client
+		}
+	case "vault":
+		var c *vault.Client
+		if c, err = actx.Driver.VaultClient(node); err == nil {
+			client = c.Client
+		}
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("creating %s client for %s: %w", api, node.Name, err)
+	}
+	return client, node.Name, nil
+}
+
+// rawList returns an arg holding one value or a list of them. Unlike
+// params.StringSlice a single string is not split on commas, which may
+// appear inside expressions and regexes.
+func rawList(args map[string]any, key string) []string {
+	if s, ok := args[key].(string); ok {
+		if s = strings.TrimSpace(s); s != "" {
+			return []string{s}
+		}
+		return nil
+	}
+	return params.StringSlice(args, key)
+}
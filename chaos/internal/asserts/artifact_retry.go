@@ -0,0 +1,187 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// artifactFailedEvent is the task event recorded when go-getter fails.
+const artifactFailedEvent = "Failed Artifact Download"
+
+// ArtifactRetryAssertion checks that artifact download failures are
+// retried with a backoff instead of failing once or hot-looping.
+type ArtifactRetryAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *ArtifactRetryAssertion) Name() string {
+	return "artifact-retry"
+}
+
+// Description returns a human-readable description.
+func (a *ArtifactRetryAssertion) Description() string {
+	return "Verify that failed artifact downloads are retried, with at least min_interval between attempts"
+}
+
+// Check collects artifact failure events from the job's allocations created
+// since the fault (or the followed allocations and their replacements) and
+// requires enough of them on at least one allocation, with no allocation
+// retrying faster than min_interval.
+func (a *ArtifactRetryAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	timeout, err := params.Duration(args, "within", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	minInterval, err := params.Duration(args, "min_interval", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	minFailures := params.Int(args, "min_failures", 2)
+	job := params.String(args, "job", "")
+	namespace := params.String(args, "namespace", "")
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+	result.Details["min_failures"] = minFailures
+	result.Details["min_interval"] = minInterval.String()
+
+	var since time.Time
+	if v, ok := driver.LookupState(actx.History, driver.StateFaultTime); ok {
+		since, _ = v.(time.Time)
+	}
+
+	var timelines map[string][]string
+	var problem string
+	ok, err := eventually(ctx, result, timeout, 5*time.Second, func() bool {
+		timelines = make(map[string][]string)
+
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			problem = err.Error()
+			return false
+		}
+		allocs, err := a.allocs(ctx, actx, client, job, namespace, since, args)
+		if err != nil {
+			problem = err.Error()
+			return false
+		}
+		if len(allocs) == 0 {
+			problem = "no allocations placed since the fault"
+			return false
+		}
+
+		var retried bool
+		timelines, retried, problem = checkRetries(allocs, since, minFailures, minInterval)
+		return retried
+	})
+	result.Details["failures"] = timelines
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	if !ok {
+		result.Message = fmt.Sprintf("Artifact retry not observed within %s: %s", timeout, problem)
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("Artifact downloads retried at least %d times, %s or more apart", minFailures, minInterval)
+	return result, nil
+}
+
+// checkRetries judges the artifact failures of every allocation. Any
+// allocation that retried faster than minInterval fails the check, so the
+// verdict does not depend on the order the API lists allocations in;
+// otherwise it needs minFailures on at least one allocation. It returns
+// the failure times per allocation and, when the check fails, why.
+func checkRetries(allocs []*nomad.Allocation, since time.Time, minFailures int, minInterval time.Duration) (map[string][]string, bool, string) {
+	timelines := make(map[string][]string)
+	var fast []string
+	enough := false
+	for _, alloc := range allocs {
+		times := artifactFailures(alloc, since)
+		var line []string
+		for _, t := range times {
+			line = append(line, t.Format(time.RFC3339))
+		}
+		timelines[alloc.ID] = line
+
+		if gap := minGap(times); gap < minInterval {
+			fast = append(fast, fmt.Sprintf("allocation %s retried after only %s", alloc.ID, gap))
+		}
+		if len(times) >= minFailures {
+			enough = true
+		}
+	}
+
+	switch {
+	case len(fast) > 0:
+		sort.Strings(fast)
+		return timelines, false, strings.Join(fast, "; ")
+	case !enough:
+		return timelines, false, fmt.Sprintf("fewer than %d artifact failures on any allocation", minFailures)
+	}
+	return timelines, true, ""
+}
+
+// allocs returns the allocations to inspect, with task states.
+func (a *ArtifactRetryAssertion) allocs(ctx context.Context, actx *driver.AssertContext, client *nomad.Client, job, namespace string, since time.Time, args map[string]any) ([]*nomad.Allocation, error) {
+	if job == "" {
+		ids, _, err := targetAllocs(actx, args)
+		if err != nil {
+			return nil, fmt.Errorf("job is required when no allocations were faulted: %w", err)
+		}
+		var out []*nomad.Allocation
+		for _, id := range ids {
+			chain, _ := allocChain(ctx, client, id)
+			out = append(out, chain...)
+		}
+		return out, nil
+	}
+
+	stubs, err := client.JobAllocations(ctx, job, namespace)
+	if err != nil {
+		return nil, err
+	}
+	var out []*nomad.Allocation
+	for i := range stubs {
+		if !since.IsZero() && time.Unix(0, stubs[i].CreateTime).Before(since) {
+			continue
+		}
+		out = append(out, &stubs[i])
+	}
+	return out, nil
+}
+
+// artifactFailures returns the sorted times of artifact failure events.
+func artifactFailures(alloc *nomad.Allocation, since time.Time) []time.Time {
+	var times []time.Time
+	for _, state := range alloc.TaskStates {
+		for _, ev := range state.Events {
+			t := time.Unix(0, ev.Time)
+			if strings.EqualFold(ev.Type, artifactFailedEvent) && !t.Before(since) {
+				times = append(times, t)
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times
+}
+
+// minGap returns the smallest interval between consecutive times.
+func minGap(times []time.Time) time.Duration {
+	gap := time.Duration(1<<63 - 1)
+	for i := 1; i < len(times); i++ {
+		if d := times[i].Sub(times[i-1]); d < gap {
+			gap = d
+		}
+	}
+	return gap
+}
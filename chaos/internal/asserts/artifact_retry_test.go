@@ -0,0 +1,71 @@
+package asserts
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+)
+
+// artifactAlloc builds an allocation whose task failed to download its
+// artifact at each of the given offsets from base.
+func artifactAlloc(t *testing.T, id string, base time.Time, offsets ...time.Duration) *nomad.Allocation {
+	t.Helper()
+	var events []string
+	for _, off := range offsets {
+		events = append(events, `{"Type": "Failed Artifact Download", "Time": `+strconv.FormatInt(base.Add(off).UnixNano(), 10)+`}`)
+	}
+	data := `{"ID": "` + id + `", "TaskStates": {"app": {"Events": [` + strings.Join(events, ",") + `]}}}`
+	var alloc nomad.Allocation
+	if err := json.Unmarshal([]byte(data), &alloc); err != nil {
+		t.Fatal(err)
+	}
+	return &alloc
+}
+
+func TestCheckRetries(t *testing.T) {
+	since := time.Unix(1000, 0)
+	spaced := artifactAlloc(t, "spaced", since, 10*time.Second, 30*time.Second, 70*time.Second)
+	hot := artifactAlloc(t, "hot", since, 10*time.Second, 11*time.Second)
+	once := artifactAlloc(t, "once", since, 10*time.Second)
+	stale := artifactAlloc(t, "stale", since, -time.Minute, -59*time.Second, 5*time.Second)
+
+	tests := []struct {
+		name    string
+		allocs  []*nomad.Allocation
+		want    bool
+		problem string
+	}{
+		{"spaced retries", []*nomad.Allocation{spaced}, true, ""},
+		{"hot loop listed last", []*nomad.Allocation{spaced, hot}, false, "allocation hot retried after only 1s"},
+		{"hot loop listed first", []*nomad.Allocation{hot, spaced}, false, "allocation hot retried after only 1s"},
+		{"too few failures", []*nomad.Allocation{once}, false, "fewer than 2 artifact failures"},
+		{"failures before the fault ignored", []*nomad.Allocation{stale, once}, false, "fewer than 2 artifact failures"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timelines, ok, problem := checkRetries(tt.allocs, since, 2, 5*time.Second)
+			if ok != tt.want || !strings.Contains(problem, tt.problem) {
+				t.Errorf("checkRetries = %v, %q, want %v, %q", ok, problem, tt.want, tt.problem)
+			}
+			for _, alloc := range tt.allocs {
+				if _, ok := timelines[alloc.ID]; !ok {
+					t.Errorf("no timeline for %s", alloc.ID)
+				}
+			}
+		})
+	}
+
+	timelines, _, _ := checkRetries([]*nomad.Allocation{spaced}, since, 2, 5*time.Second)
+	var want []string
+	for _, off := range []time.Duration{10 * time.Second, 30 * time.Second, 70 * time.Second} {
+		want = append(want, since.Add(off).Format(time.RFC3339))
+	}
+	if got := timelines["spaced"]; !slices.Equal(got, want) {
+		t.Errorf("timeline = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,288 @@
+package asserts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/jsonpath"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// maxOutputDetail caps the stdout and stderr kept per node in the report.
+const maxOutputDetail = 512
+
+// CommandAssertion checks the output of a shell command run on nodes.
+type CommandAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *CommandAssertion) Name() string {
+	return "command"
+}
+
+// Description returns a human-readable description.
+func (a *CommandAssertion) Description() string {
+	return "Verify a shell command's exit code, output or JSON result on selected nodes"
+}
+
+// commandCheck holds the matchers a node's command output must satisfy.
+type commandCheck struct {
+	exitCode  int
+	anyExit   bool
+	stdout    *regexp.Regexp
+	stderr    *regexp.Regexp
+	jsonPath  string
+	jsonValue string
+	hasValue  bool
+}
+
+// Check runs the command on every selected node (default all) and matches
+// each node's exit code (default 0, or "any"), stdout and stderr regexes
+// and a JSON path in stdout. The check passes when the nodes required by
+// require (all, any or a count) match; with within set it is retried
+// until then.
+func (a *CommandAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	command := params.String(args, "command", "")
+	if command == "" {
+		return nil, fmt.Errorf("command is required")
+	}
+	timeout, err := params.Duration(args, "within", 0)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := params.Duration(args, "interval", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	runTimeout, err := params.Duration(args, "timeout", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	sudo := params.Bool(args, "sudo", false)
+	check, err := parseCommandCheck(args)
+	if err != nil {
+		return nil, err
+	}
+	require := params.String(args, "require", "all")
+
+	selectors := params.StringSlice(args, "nodes")
+	if len(selectors) == 0 {
+		selectors = []string{"all"}
+	}
+	nodes, err := driver.SelectNodes(ctx, actx.Driver, actx.Cluster, selectors)
+	if err != nil {
+		return nil, err
+	}
+	need, err := requiredPasses(require, len(nodes))
+	if err != nil {
+		return nil, err
+	}
+
+	if sudo {
+		command = "sh -c " + shellQuote(command)
+	}
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["command"] = params.String(args, "command", "")
+	result.Details["require"] = require
+	if timeout > 0 {
+		result.Details["timeout"] = timeout.String()
+	}
+
+	var outcomes map[string]map[string]any
+	var failing []string
+	ok, err := eventually(ctx, result, timeout, interval, func() bool {
+		outcomes = make(map[string]map[string]any, len(nodes))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, node := range nodes {
+			wg.Add(1)
+			go func(node driver.Node) {
+				defer wg.Done()
+				outcome := runCommand(ctx, actx, node, command, sudo, runTimeout, check)
+				mu.Lock()
+				outcomes[node.Name] = outcome
+				mu.Unlock()
+			}(node)
+		}
+		wg.Wait()
+
+		failing = failing[:0]
+		for name, outcome := range outcomes {
+			if outcome["passed"] != true {
+				failing = append(failing, fmt.Sprintf("%s: %s", name, outcome["reason"]))
+			}
+		}
+		sort.Strings(failing)
+		return len(nodes)-len(failing) >= need
+	})
+	result.Details["nodes"] = outcomes
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	passed := len(nodes) - len(failing)
+	if !ok {
+		result.Message = fmt.Sprintf("%d/%d nodes passed, %d required: %s", passed, len(nodes), need, strings.Join(failing, "; "))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d/%d nodes passed (%d required)", passed, len(nodes), need)
+	return result, nil
+}
+
+// parseCommandCheck reads the exit_code, stdout, stderr, json_path and
+// json_value args.
+func parseCommandCheck(args map[string]any) (*commandCheck, error) {
+	c := &commandCheck{}
+	switch code := params.String(args, "exit_code", "0"); code {
+	case "any":
+		c.anyExit = true
+	default:
+		n, err := strconv.Atoi(code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exit_code %q: must be a number or any", code)
+		}
+		c.exitCode = n
+	}
+
+	var err error
+	if expr := params.String(args, "stdout", ""); expr != "" {
+		if c.stdout, err = regexp.Compile(expr); err != nil {
+			return nil, fmt.Errorf("invalid stdout regex: %w", err)
+		}
+	}
+	if expr := params.String(args, "stderr", ""); expr != "" {
+		if c.stderr, err = regexp.Compile(expr); err != nil {
+			return nil, fmt.Errorf("invalid stderr regex: %w", err)
+		}
+	}
+
+	c.jsonPath = params.String(args, "json_path", "")
+	_, c.hasValue = args["json_value"]
+	c.jsonValue = params.String(args, "json_value", "")
+	if c.hasValue && c.jsonPath == "" {
+		return nil, fmt.Errorf("json_value requires json_path")
+	}
+	if c.jsonPath != "" {
+		if _, err := jsonpath.Lookup(nil, c.jsonPath); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// runCommand runs the command on one node and matches its output,
+// returning the per-node outcome reported in Details.
+func runCommand(ctx context.Context, actx *driver.AssertContext, node driver.Node, command string, sudo bool, timeout time.Duration, check *commandCheck) map[string]any {
+	outcome := map[string]any{"passed": false}
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ssh, err := actx.Driver.SSH(runCtx, node)
+	if err != nil {
+		outcome["reason"] = fmt.Sprintf("connecting: %v", err)
+		return outcome
+	}
+	defer ssh.Close()
+
+	run := ssh.Run
+	if sudo {
+		run = ssh.RunWithSudo
+	}
+	stdout, stderr, code, err := run(runCtx, command)
+	if err != nil {
+		outcome["reason"] = fmt.Sprintf("running command: %v", err)
+		return outcome
+	}
+	outcome["exit_code"] = code
+	outcome["stdout"] = truncate(strings.TrimSpace(stdout), maxOutputDetail)
+	if s := strings.TrimSpace(stderr); s != "" {
+		outcome["stderr"] = truncate(s, maxOutputDetail)
+	}
+
+	if reason := check.match(code, stdout, stderr, outcome); reason != "" {
+		outcome["reason"] = reason
+		return outcome
+	}
+	outcome["passed"] = true
+	return outcome
+}
+
+// match applies the matchers to one command's result, returning why it
+// failed or "" if it passed. A selected JSON value is recorded on outcome.
+func (c *commandCheck) match(code int, stdout, stderr string, outcome map[string]any) string {
+	if !c.anyExit && code != c.exitCode {
+		return fmt.Sprintf("exit %d, want %d", code, c.exitCode)
+	}
+	if c.stdout != nil && !c.stdout.MatchString(stdout) {
+		return fmt.Sprintf("stdout does not match %q", c.stdout)
+	}
+	if c.stderr != nil && !c.stderr.MatchString(stderr) {
+		return fmt.Sprintf("stderr does not match %q", c.stderr)
+	}
+	if c.jsonPath == "" {
+		return ""
+	}
+
+	var doc any
+	if err := json.Unmarshal([]byte(stdout), &doc); err != nil {
+		return fmt.Sprintf("stdout is not JSON: %v", err)
+	}
+	values, _ := jsonpath.Lookup(doc, c.jsonPath)
+	if len(values) == 0 {
+		return fmt.Sprintf("%s selects nothing", c.jsonPath)
+	}
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = jsonpath.Format(v)
+	}
+	outcome["json_value"] = strings.Join(formatted, ", ")
+	if c.hasValue && !slices.Contains(formatted, c.jsonValue) {
+		return fmt.Sprintf("%s is %s, want %s", c.jsonPath, strings.Join(formatted, ", "), c.jsonValue)
+	}
+	return ""
+}
+
+// requiredPasses converts a require arg of all, any or a count into the
+// number of nodes that must pass out of total.
+func requiredPasses(require string, total int) (int, error) {
+	switch require {
+	case "all":
+		return total, nil
+	case "any":
+		return 1, nil
+	}
+	n, err := strconv.Atoi(require)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid require %q: must be all, any or a positive count", require)
+	}
+	if n > total {
+		return 0, fmt.Errorf("require %d exceeds the %d selected nodes", n, total)
+	}
+	return n, nil
+}
+
+// shellQuote quotes s as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// truncate shortens s to at most n bytes, marking the cut.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
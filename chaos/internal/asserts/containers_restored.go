@@ -0,0 +1,111 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// ContainersRestoredAssertion checks that docker tasks running before a
+// fault have running containers again, in place or in replacements.
+type ContainersRestoredAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *ContainersRestoredAssertion) Name() string {
+	return "containers-restored"
+}
+
+// Description returns a human-readable description.
+func (a *ContainersRestoredAssertion) Description() string {
+	return "Verify that allocations with containers before a fault have running containers again"
+}
+
+// Check follows each allocation to its latest replacement and looks for a
+// running container labelled with its ID on the client.
+func (a *ContainersRestoredAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	timeout, err := params.Duration(args, "within", 3*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+
+	allocIDs, _, err := targetAllocs(actx, args)
+	if err != nil {
+		result.Message = err.Error()
+		return result, nil
+	}
+	if len(allocIDs) == 0 {
+		result.Success = true
+		result.Message = "No containers were running before the fault"
+		return result, nil
+	}
+
+	var containers, missing map[string]string
+	ok, err := eventually(ctx, result, timeout, 3*time.Second, func() bool {
+		containers = make(map[string]string)
+		missing = make(map[string]string)
+
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			result.Details["error"] = err.Error()
+			return false
+		}
+		for _, id := range allocIDs {
+			container, err := a.container(ctx, actx, client, id)
+			if err != nil {
+				missing[id] = err.Error()
+				continue
+			}
+			containers[id] = container
+		}
+		return len(missing) == 0
+	})
+	result.Details["containers"] = containers
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	if !ok {
+		result.Details["missing"] = missing
+		result.Message = fmt.Sprintf("%d/%d allocations have no running container within %s", len(missing), len(allocIDs), timeout)
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("All %d allocations have running containers", len(allocIDs))
+	return result, nil
+}
+
+// container returns "node: containerID" for the latest allocation in id's chain.
+func (a *ContainersRestoredAssertion) container(ctx context.Context, actx *driver.AssertContext, client *nomad.Client, id string) (string, error) {
+	chain, err := allocChain(ctx, client, id)
+	if len(chain) == 0 {
+		return "", err
+	}
+	alloc := chain[len(chain)-1]
+	if alloc.ClientStatus != nomad.AllocClientStatusRunning {
+		return "", fmt.Errorf("allocation %s is %s", alloc.ID, alloc.ClientStatus)
+	}
+
+	node, err := allocNode(ctx, actx, client, alloc)
+	if err != nil {
+		return "", err
+	}
+	out, err := runOnNode(ctx, actx, *node, "docker ps -q --filter label="+nomad.DockerAllocIDLabel+"="+alloc.ID)
+	if err != nil {
+		return "", fmt.Errorf("listing containers on %s: %w", node.Name, err)
+	}
+	ids := strings.Fields(out)
+	if len(ids) == 0 {
+		return "", fmt.Errorf("no running container for %s on %s", alloc.ID, node.Name)
+	}
+	return node.Name + ": " + strings.Join(ids, ","), nil
+}
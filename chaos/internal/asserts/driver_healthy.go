@@ -0,0 +1,130 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// DriverHealthyAssertion checks the fingerprinted health of task drivers
+// on client nodes.
+type DriverHealthyAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *DriverHealthyAssertion) Name() string {
+	return "driver-healthy"
+}
+
+// Description returns a human-readable description.
+func (a *DriverHealthyAssertion) Description() string {
+	return "Verify that task drivers are detected and healthy (or, with healthy=false, unhealthy) on client nodes"
+}
+
+// Check polls node driver status until every selected node reports each
+// driver with the expected health. Nodes are selected by the nodes, class,
+// datacenter and labels args; without them every client is checked.
+func (a *DriverHealthyAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	timeout, err := params.Duration(args, "within", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	drivers := params.StringSlice(args, "driver")
+	if len(drivers) == 0 {
+		drivers = []string{"docker"}
+	}
+	healthy := params.Bool(args, "healthy", true)
+	filter, err := parseNodeFilter(args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+	result.Details["drivers"] = drivers
+	result.Details["expect_healthy"] = healthy
+	if !filter.empty() {
+		result.Details["filter"] = filter.String()
+	}
+
+	var statuses, wrong map[string]string
+	var nodeCount int
+	ok, err := eventually(ctx, result, timeout, 2*time.Second, func() bool {
+		statuses = make(map[string]string)
+		wrong = make(map[string]string)
+
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			result.Details["error"] = err.Error()
+			return false
+		}
+		nodes, err := selectClientNodes(ctx, actx, client, filter)
+		if err != nil {
+			result.Details["error"] = err.Error()
+			return false
+		}
+		if len(nodes) == 0 {
+			result.Details["error"] = "no client nodes match"
+			return false
+		}
+		delete(result.Details, "error")
+		nodeCount = len(nodes)
+
+		for _, n := range nodes {
+			for _, name := range drivers {
+				key := n.Name + "/" + name
+				info := n.Drivers[name]
+				status := "not detected"
+				if info != nil && info.Detected {
+					status = "unhealthy"
+					if info.Healthy {
+						status = "healthy"
+					}
+				}
+				if info != nil && info.HealthDescription != "" {
+					status += ": " + info.HealthDescription
+				}
+				statuses[key] = status
+
+				// A missing driver only satisfies an unhealthy expectation
+				detected := info != nil && info.Detected
+				if detected && info.Healthy != healthy || !detected && healthy {
+					wrong[key] = status
+				}
+			}
+		}
+		return len(wrong) == 0
+	})
+	result.Details["statuses"] = statuses
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	want := "healthy"
+	if !healthy {
+		want = "unhealthy"
+	}
+	if !ok {
+		if msg, failed := result.Details["error"].(string); failed {
+			result.Message = fmt.Sprintf("Cannot check drivers: %s", msg)
+			return result, nil
+		}
+		result.Details["wrong"] = wrong
+		var descs []string
+		for key, status := range wrong {
+			descs = append(descs, key+" "+status)
+		}
+		sort.Strings(descs)
+		result.Message = fmt.Sprintf("%d/%d driver checks not %s within %s: %s", len(wrong), len(statuses), want, timeout, strings.Join(descs, "; "))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%s %s on all %d nodes", strings.Join(drivers, ", "), want, nodeCount)
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// leaderClient returns a Nomad API client for the current leader.
+func leaderClient(ctx context.Context, actx *driver.AssertContext) (*nomad.Client, error) {
+	leader, err := actx.Driver.GetNomadLeader(ctx, actx.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("finding leader: %w", err)
+	}
+	return actx.Driver.NomadClient(*leader)
+}
+
+// targetAllocs returns the allocation IDs an assertion follows: the allocs
+// arg if given, otherwise those recorded by the latest action in the run
+// that disturbed allocations, along with when that action started.
+func targetAllocs(actx *driver.AssertContext, args map[string]any) ([]string, time.Time, error) {
+	if ids := params.StringSlice(args, "allocs"); len(ids) > 0 {
+		return ids, time.Time{}, nil
+	}
+
+	for i := len(actx.History) - 1; i >= 0; i-- {
+		state := actx.History[i].State
+		if ids, ok := state[driver.StateAllocIDs].([]string); ok {
+			since, _ := state[driver.StateFaultTime].(time.Time)
+			return ids, since, nil
+		}
+	}
+	return nil, time.Time{}, fmt.Errorf("no allocs given and no allocation fault earlier in this run")
+}
+
+// allocChain returns an allocation followed by its replacements, oldest
+// first, by walking NextAllocation links.
+func allocChain(ctx context.Context, client *nomad.Client, id string) ([]*nomad.Allocation, error) {
+	var chain []*nomad.Allocation
+	for id != "" && len(chain) < 20 {
+		alloc, err := client.Allocation(ctx, id)
+		if err != nil {
+			return chain, fmt.Errorf("reading allocation %s: %w", id, err)
+		}
+		chain = append(chain, alloc)
+		id = alloc.NextAllocation
+	}
+	return chain, nil
+}
+
+// allocNode maps an allocation to the discovered client node running it.
+func allocNode(ctx context.Context, actx *driver.AssertContext, client *nomad.Client, alloc *nomad.Allocation) (*driver.Node, error) {
+	node, err := client.Node(ctx, alloc.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("reading node of allocation %s: %w", alloc.ID, err)
+	}
+	return actx.Cluster.NodeByIP(node.IP())
+}
+
+// runOnNode runs a command with sudo on a node. A non-zero exit code is
+// returned as an error carrying stderr.
+func runOnNode(ctx context.Context, actx *driver.AssertContext, node driver.Node, cmd string) (string, error) {
+	ssh, err := actx.Driver.SSH(ctx, node)
+	if err != nil {
+		return "", fmt.Errorf("connecting to %s: %w", node.Name, err)
+	}
+	defer ssh.Close()
+
+	stdout, stderr, code, err := ssh.RunWithSudo(ctx, cmd)
+	if err != nil {
+		return stdout, err
+	}
+	if code != 0 {
+		return stdout, fmt.Errorf("exit %d: %s", code, strings.TrimSpace(stderr))
+	}
+	return stdout, nil
+}
+
+// eventually calls check every interval until it succeeds or timeout
+// expires, recording the attempts and duration on result. It returns
+// whether the check passed; ctx cancellation is returned as an error.
+func eventually(ctx context.Context, result *Result, timeout, interval time.Duration, check func() bool) (bool, error) {
+	start := time.Now()
+	deadline := start.Add(timeout)
+	defer func() { result.Duration = time.Since(start) }()
+
+	for {
+		result.Attempts++
+		if check() {
+			return true, nil
+		}
+		if !time.Now().Before(deadline) {
+			return false, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package asserts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/libvirt-standalone/chaos/internal/httpapi"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+)
+
+// allocServer serves /v1/allocation/:id from next, which maps each
+// allocation to its replacement ("" for none).
+func allocServer(t *testing.T, next map[string]string) *nomad.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v1/allocation/")
+		n, ok := next[id]
+		if !ok {
+			http.Error(w, "alloc not found", http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"ID": id, "NextAllocation": n})
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := nomad.NewClient(httpapi.Config{Address: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func chainIDs(chain []*nomad.Allocation) []string {
+	ids := make([]string, len(chain))
+	for i, a := range chain {
+		ids[i] = a.ID
+	}
+	return ids
+}
+
+func TestAllocChain(t *testing.T) {
+	client := allocServer(t, map[string]string{
+		"a": "b",
+		"b": "c",
+		"c": "",
+		"d": "gone",
+	})
+
+	chain, err := allocChain(context.Background(), client, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := chainIDs(chain), []string{"a", "b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("allocChain = %v, want %v", got, want)
+	}
+
+	chain, err = allocChain(context.Background(), client, "d")
+	if err == nil || !strings.Contains(err.Error(), "reading allocation gone") {
+		t.Errorf("allocChain with a missing replacement: err = %v", err)
+	}
+	if got, want := chainIDs(chain), []string{"d"}; !slices.Equal(got, want) {
+		t.Errorf("partial chain = %v, want %v", got, want)
+	}
+}
+
+func TestAllocChainStopsAtLimit(t *testing.T) {
+	next := make(map[string]string)
+	for i := range 30 {
+		next[fmt.Sprint(i)] = fmt.Sprint(i + 1)
+	}
+	client := allocServer(t, next)
+
+	chain, err := allocChain(context.Background(), client, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 20 {
+		t.Errorf("allocChain followed %d allocations, want 20", len(chain))
+	}
+}
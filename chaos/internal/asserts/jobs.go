@@ -0,0 +1,391 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// JobHealthyAssertion checks that a job is running with the expected
+// number of healthy allocations in each task group.
+type JobHealthyAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *JobHealthyAssertion) Name() string {
+	return "job-healthy"
+}
+
+// Description returns a human-readable description.
+func (a *JobHealthyAssertion) Description() string {
+	return "Verify that a job is running with the expected count of healthy allocations per group"
+}
+
+// Check polls the job and its allocations until the job is running and each
+// group has at least count healthy allocations (default: the group's count
+// in the job spec).
+func (a *JobHealthyAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	jobID, namespace, err := jobArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := params.Duration(args, "within", 2*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	count := params.Int(args, "count", 0)
+	group := params.String(args, "group", "")
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+	result.Details["job"] = jobID
+
+	var problems []string
+	ok, err := eventually(ctx, result, timeout, 2*time.Second, func() bool {
+		problems = nil
+
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			problems = []string{err.Error()}
+			return false
+		}
+		job, err := client.Job(ctx, jobID, namespace)
+		if err != nil {
+			problems = []string{fmt.Sprintf("reading job: %v", err)}
+			return false
+		}
+		allocs, err := client.JobAllocations(ctx, jobID, namespace)
+		if err != nil {
+			problems = []string{fmt.Sprintf("listing allocations: %v", err)}
+			return false
+		}
+
+		result.Details["status"] = job.Status
+		if job.Status != nomad.JobStatusRunning {
+			problems = append(problems, fmt.Sprintf("job is %s", job.Status))
+		}
+
+		healthy := make(map[string]int)
+		for _, alloc := range allocs {
+			if alloc.DesiredStatus == "run" && alloc.ClientStatus == nomad.AllocClientStatusRunning && alloc.IsHealthy() {
+				healthy[alloc.TaskGroup]++
+			}
+		}
+
+		groups := make(map[string]string)
+		matched := false
+		for _, tg := range job.TaskGroups {
+			if group != "" && tg.Name != group {
+				continue
+			}
+			matched = true
+			want := tg.Count
+			if count > 0 {
+				want = count
+			}
+			groups[tg.Name] = fmt.Sprintf("%d/%d healthy", healthy[tg.Name], want)
+			if healthy[tg.Name] < want {
+				problems = append(problems, fmt.Sprintf("group %s has %d/%d healthy allocations", tg.Name, healthy[tg.Name], want))
+			}
+		}
+		result.Details["groups"] = groups
+		if !matched {
+			problems = append(problems, fmt.Sprintf("job has no group %q", group))
+		}
+		return len(problems) == 0
+	})
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	if !ok {
+		result.Details["problems"] = problems
+		result.Message = fmt.Sprintf("Job %s not healthy within %s: %s", jobID, timeout, strings.Join(problems, "; "))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("Job %s running with all groups healthy", jobID)
+	return result, nil
+}
+
+// DeploymentSuccessfulAssertion checks that a job's latest deployment
+// succeeded.
+type DeploymentSuccessfulAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *DeploymentSuccessfulAssertion) Name() string {
+	return "deployment-successful"
+}
+
+// Description returns a human-readable description.
+func (a *DeploymentSuccessfulAssertion) Description() string {
+	return "Verify that a job's latest deployment reaches successful within the timeout"
+}
+
+// Check polls the latest deployment until it is successful. A failed or
+// cancelled deployment fails the check without waiting out the timeout.
+func (a *DeploymentSuccessfulAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	jobID, namespace, err := jobArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := params.Duration(args, "within", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	minVersion := uint64(params.Int(args, "min_version", 0))
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+	result.Details["job"] = jobID
+
+	var deployment *nomad.Deployment
+	var lastErr error
+	_, err = eventually(ctx, result, timeout, 2*time.Second, func() bool {
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			lastErr = err
+			return false
+		}
+		deployment, lastErr = client.LatestDeployment(ctx, jobID, namespace)
+		if lastErr != nil || deployment == nil || deployment.JobVersion < minVersion {
+			return false
+		}
+		switch deployment.Status {
+		case nomad.DeploymentStatusSuccessful, nomad.DeploymentStatusFailed, nomad.DeploymentStatusCancelled:
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	if deployment == nil {
+		if lastErr != nil {
+			result.Details["error"] = lastErr.Error()
+		}
+		result.Message = fmt.Sprintf("Job %s has no deployment", jobID)
+		return result, nil
+	}
+
+	groups := make(map[string]string)
+	for name, state := range deployment.TaskGroups {
+		groups[name] = fmt.Sprintf("%d/%d healthy, %d unhealthy, %d placed",
+			state.HealthyAllocs, state.DesiredTotal, state.UnhealthyAllocs, state.PlacedAllocs)
+	}
+	result.Details["deployment"] = deployment.ID
+	result.Details["job_version"] = deployment.JobVersion
+	result.Details["status"] = deployment.Status
+	result.Details["status_description"] = deployment.StatusDescription
+	result.Details["groups"] = groups
+
+	switch {
+	case deployment.JobVersion < minVersion:
+		result.Message = fmt.Sprintf("No deployment of job version %d or later within %s (latest is version %d)", minVersion, timeout, deployment.JobVersion)
+	case deployment.Status == nomad.DeploymentStatusSuccessful:
+		result.Success = true
+		result.Message = fmt.Sprintf("Deployment %s of %s version %d successful", shortID(deployment.ID), jobID, deployment.JobVersion)
+	default:
+		result.Message = fmt.Sprintf("Deployment %s of %s is %s: %s", shortID(deployment.ID), jobID, deployment.Status, deployment.StatusDescription)
+	}
+	return result, nil
+}
+
+// AllocsRescheduledAssertion checks that a job's allocations on a faulted
+// node were replaced on other nodes.
+type AllocsRescheduledAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *AllocsRescheduledAssertion) Name() string {
+	return "allocs-rescheduled"
+}
+
+// Description returns a human-readable description.
+func (a *AllocsRescheduledAssertion) Description() string {
+	return "Verify that a job's allocations from a faulted node are replaced by healthy allocations on other nodes"
+}
+
+// Check finds the job's allocations on the faulted node that predate the
+// fault and polls until the newest allocation replacing each one runs
+// healthy on a different node. The node is the node arg (a discovered name
+// or Nomad node ID) or the node recorded by an earlier step.
+func (a *AllocsRescheduledAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	jobID, namespace, err := jobArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := params.Duration(args, "within", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	var since time.Time
+	if v, ok := driver.LookupState(actx.History, driver.StateFaultTime); ok {
+		since, _ = v.(time.Time)
+	}
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+	result.Details["job"] = jobID
+
+	client, err := leaderClient(ctx, actx)
+	if err != nil {
+		result.Message = err.Error()
+		return result, nil
+	}
+	nodeID, err := faultedNodeID(ctx, actx, client, params.String(args, "node", ""))
+	if err != nil {
+		result.Message = err.Error()
+		return result, nil
+	}
+	result.Details["node_id"] = nodeID
+
+	var originals []string
+	replacements := make(map[string]string)
+	pending := make(map[string]string)
+	ok, err := eventually(ctx, result, timeout, 3*time.Second, func() bool {
+		clear(replacements)
+		clear(pending)
+
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			result.Details["error"] = err.Error()
+			return false
+		}
+		allocs, err := client.JobAllocations(ctx, jobID, namespace)
+		if err != nil {
+			result.Details["error"] = fmt.Sprintf("listing allocations: %v", err)
+			return false
+		}
+
+		byID := make(map[string]nomad.Allocation)
+		next := make(map[string]string)
+		for _, alloc := range allocs {
+			byID[alloc.ID] = alloc
+			if alloc.PreviousAllocation != "" {
+				next[alloc.PreviousAllocation] = alloc.ID
+			}
+		}
+
+		// Originals are fixed on the first poll; later polls follow them
+		if originals == nil {
+			originals = faultedAllocs(allocs, nodeID, since)
+		}
+
+		for _, id := range originals {
+			latest := id
+			for n := next[latest]; n != ""; n = next[latest] {
+				latest = n
+			}
+			alloc := byID[latest]
+			switch {
+			case latest == id:
+				pending[id] = fmt.Sprintf("not replaced (%s on the faulted node)", byID[id].ClientStatus)
+			case alloc.NodeID == nodeID:
+				pending[id] = fmt.Sprintf("replacement %s placed on the faulted node", shortID(alloc.ID))
+			case !alloc.IsHealthy():
+				pending[id] = fmt.Sprintf("replacement %s on %s is %s", shortID(alloc.ID), alloc.NodeName, alloc.ClientStatus)
+			default:
+				replacements[id] = alloc.ID + "@" + alloc.NodeName
+			}
+		}
+		return len(pending) == 0
+	})
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	result.Details["allocs"] = originals
+	result.Details["replacements"] = replacements
+	if len(originals) == 0 {
+		result.Message = fmt.Sprintf("Job %s had no allocations on the faulted node", jobID)
+		return result, nil
+	}
+	if !ok {
+		result.Details["pending"] = pending
+		result.Message = fmt.Sprintf("%d/%d allocations not rescheduled within %s", len(pending), len(originals), timeout)
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("All %d allocations of %s rescheduled on other nodes", len(originals), jobID)
+	return result, nil
+}
+
+// faultedAllocs returns the sorted IDs of the allocations on nodeID that
+// predate the fault, excluding replacements of allocations that were
+// already there. An allocation that completed is included only when the
+// scheduler stopped or migrated it, as a drain does, or it has been
+// replaced; one that simply finished its work is not expected to move.
+func faultedAllocs(allocs []nomad.Allocation, nodeID string, since time.Time) []string {
+	byID := make(map[string]nomad.Allocation)
+	replaced := make(map[string]bool)
+	for _, alloc := range allocs {
+		byID[alloc.ID] = alloc
+		if alloc.PreviousAllocation != "" {
+			replaced[alloc.PreviousAllocation] = true
+		}
+	}
+
+	ids := []string{}
+	for _, alloc := range allocs {
+		if alloc.NodeID != nodeID || alloc.PreviousAllocation != "" && byID[alloc.PreviousAllocation].NodeID == nodeID {
+			continue
+		}
+		if !since.IsZero() && time.Unix(0, alloc.CreateTime).After(since) {
+			continue
+		}
+		if alloc.ClientStatus == nomad.AllocClientStatusComplete && alloc.DesiredStatus == "run" && !alloc.Migrating() && !replaced[alloc.ID] {
+			continue
+		}
+		ids = append(ids, alloc.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// jobArgs returns the required job arg and the optional namespace.
+func jobArgs(args map[string]any) (string, string, error) {
+	job := params.String(args, "job", "")
+	if job == "" {
+		return "", "", fmt.Errorf("job is required")
+	}
+	return job, params.String(args, "namespace", ""), nil
+}
+
+// faultedNodeID resolves the node arg, a discovered name or Nomad node ID,
+// falling back to the Nomad node recorded by an earlier step.
+func faultedNodeID(ctx context.Context, actx *driver.AssertContext, client *nomad.Client, name string) (string, error) {
+	if name == "" {
+		if v, ok := driver.LookupState(actx.History, driver.StateNomadNodeID); ok {
+			if id, ok := v.(string); ok {
+				return id, nil
+			}
+		}
+		return "", fmt.Errorf("no node given and no node recorded earlier in this run")
+	}
+
+	nodes, err := selectClientNodes(ctx, actx, client, nodeFilter{names: []string{name}})
+	if err != nil {
+		return "", err
+	}
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("client node %q not found", name)
+	}
+	return nodes[0].ID, nil
+}
+
+// shortID returns the first segment of a UUID, as the Nomad CLI shows it.
+func shortID(id string) string {
+	return strings.SplitN(id, "-", 2)[0]
+}
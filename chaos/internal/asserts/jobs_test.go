@@ -0,0 +1,43 @@
+package asserts
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+)
+
+func TestFaultedAllocs(t *testing.T) {
+	fault := time.Unix(1000, 0)
+	before := fault.Add(-time.Minute).UnixNano()
+	after := fault.Add(time.Minute).UnixNano()
+
+	data := `[
+		{"ID": "running", "NodeID": "n1", "DesiredStatus": "run", "ClientStatus": "running", "CreateTime": ` + strconv.FormatInt(before, 10) + `},
+		{"ID": "drained", "NodeID": "n1", "DesiredStatus": "stop", "ClientStatus": "complete", "DesiredTransition": {"Migrate": true}, "CreateTime": ` + strconv.FormatInt(before, 10) + `},
+		{"ID": "migrated", "NodeID": "n1", "DesiredStatus": "run", "ClientStatus": "complete", "DesiredTransition": {"Migrate": true}, "CreateTime": ` + strconv.FormatInt(before, 10) + `},
+		{"ID": "finished", "NodeID": "n1", "DesiredStatus": "run", "ClientStatus": "complete", "CreateTime": ` + strconv.FormatInt(before, 10) + `},
+		{"ID": "replaced", "NodeID": "n1", "DesiredStatus": "run", "ClientStatus": "complete", "CreateTime": ` + strconv.FormatInt(before, 10) + `},
+		{"ID": "replacement", "NodeID": "n2", "PreviousAllocation": "replaced", "DesiredStatus": "run", "ClientStatus": "running", "CreateTime": ` + strconv.FormatInt(after, 10) + `},
+		{"ID": "restarted", "NodeID": "n1", "PreviousAllocation": "running", "DesiredStatus": "run", "ClientStatus": "pending", "CreateTime": ` + strconv.FormatInt(before, 10) + `},
+		{"ID": "new", "NodeID": "n1", "DesiredStatus": "run", "ClientStatus": "running", "CreateTime": ` + strconv.FormatInt(after, 10) + `},
+		{"ID": "elsewhere", "NodeID": "n2", "DesiredStatus": "run", "ClientStatus": "running", "CreateTime": ` + strconv.FormatInt(before, 10) + `}
+	]`
+	var allocs []nomad.Allocation
+	if err := json.Unmarshal([]byte(data), &allocs); err != nil {
+		t.Fatal(err)
+	}
+
+	got := faultedAllocs(allocs, "n1", fault)
+	want := []string{"drained", "migrated", "replaced", "running"}
+	if !slices.Equal(got, want) {
+		t.Errorf("faultedAllocs = %v, want %v", got, want)
+	}
+
+	if got := faultedAllocs(allocs, "n3", fault); got == nil || len(got) != 0 {
+		t.Errorf("faultedAllocs on an empty node = %#v, want an empty list", got)
+	}
+}
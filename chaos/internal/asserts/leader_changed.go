@@ -0,0 +1,124 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// LeaderChangedAssertion checks that a different server took over
+// leadership after a fault.
+type LeaderChangedAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *LeaderChangedAssertion) Name() string {
+	return "leader-changed"
+}
+
+// Description returns a human-readable description.
+func (a *LeaderChangedAssertion) Description() string {
+	return "Verify that a different server than the one leading before the fault is now leader"
+}
+
+// Check polls for a leader other than the previous one: the previous arg,
+// or the leader recorded by kill-leader or capture-leader earlier in the
+// run. The time from the fault to the first poll that saw the new leader
+// is reported as time_to_observed_leader. Nomad does not expose when an
+// election finished, so this is an upper bound on the election time, and
+// when the first poll already sees a new leader it mostly reflects when
+// the assertion started.
+func (a *LeaderChangedAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	timeout, err := params.Duration(args, "within", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := params.Duration(args, "poll", 500*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, since := params.String(args, "previous", ""), time.Time{}
+	if previous == "" {
+		previous, since = previousLeader(actx.History)
+	}
+	if previous == "" {
+		return nil, fmt.Errorf("no previous given and no leader captured earlier in this run (use capture-leader)")
+	}
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+	result.Details["previous_leader"] = previous
+	if !since.IsZero() {
+		result.Details["fault_time"] = since.Format(time.RFC3339Nano)
+	}
+
+	var current string
+	var lastErr error
+	var observed time.Time
+	polls := 0
+	ok, err := eventually(ctx, result, timeout, interval, func() bool {
+		polls++
+		polled := time.Now()
+		leader, err := actx.Driver.GetNomadLeader(ctx, actx.Cluster)
+		if err != nil {
+			lastErr = err
+			current = ""
+			return false
+		}
+		current = leader.Name
+		if current == previous {
+			return false
+		}
+		observed = polled
+		return true
+	})
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	if !ok {
+		switch {
+		case current == previous:
+			result.Message = fmt.Sprintf("%s is still leader after %s", previous, timeout)
+		case lastErr != nil:
+			result.Details["error"] = lastErr.Error()
+			result.Message = fmt.Sprintf("No leader elected within %s: %v", timeout, lastErr)
+		}
+		return result, nil
+	}
+
+	result.Success = true
+	result.Details["new_leader"] = current
+	result.Message = fmt.Sprintf("Leadership moved from %s to %s", previous, current)
+	if !since.IsZero() {
+		elapsed := observed.Sub(since).Round(time.Millisecond)
+		result.Details["time_to_observed_leader"] = elapsed.String()
+		result.Details["observed_on_first_poll"] = polls == 1
+		result.Message += fmt.Sprintf(", observed %s after the fault", elapsed)
+	}
+	return result, nil
+}
+
+// previousLeader returns the most recently recorded pre-fault leader and
+// when the fault started: the latest fault time recorded at or after the
+// capture, or the capture time itself.
+func previousLeader(history []*driver.ActionContext) (string, time.Time) {
+	for i := len(history) - 1; i >= 0; i-- {
+		leader, ok := history[i].State[driver.StateLeader].(string)
+		if !ok {
+			continue
+		}
+		if v, ok := driver.LookupState(history[i:], driver.StateFaultTime); ok {
+			if since, ok := v.(time.Time); ok {
+				return leader, since
+			}
+		}
+		since, _ := history[i].State["captured_at"].(time.Time)
+		return leader, since
+	}
+	return "", time.Time{}
+}
@@ -0,0 +1,227 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// LeaderStableAssertion checks that leadership does not flap over a window.
+type LeaderStableAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *LeaderStableAssertion) Name() string {
+	return "leader-stable"
+}
+
+// Description returns a human-readable description.
+func (a *LeaderStableAssertion) Description() string {
+	return "Verify that the Nomad leader and raft term stay stable over a sampling window"
+}
+
+// leaderSample is the cluster's view of leadership at one point in time.
+type leaderSample struct {
+	leader string
+	term   uint64
+	views  map[string]string
+}
+
+// Check samples every server's leader and raft term for the whole
+// duration. It fails if leadership moved more than max_changes times or
+// the term rose by more than max_term_increase. Each change of leader or
+// term is reported in the timeline detail.
+func (a *LeaderStableAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	duration, err := params.Duration(args, "duration", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := params.Duration(args, "interval", time.Second)
+	if err != nil {
+		return nil, err
+	}
+	maxChanges := params.Int(args, "max_changes", 0)
+	maxTermIncrease := uint64(params.Int(args, "max_term_increase", 0))
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["duration"] = duration.String()
+	result.Details["max_changes"] = maxChanges
+	result.Details["max_term_increase"] = maxTermIncrease
+
+	var timeline []map[string]any
+	var prev *leaderSample
+	var firstTerm, lastTerm uint64
+	var lastLeader string
+	changes := 0
+	var leaderless time.Duration
+
+	start := time.Now()
+	deadline := start.Add(duration)
+	for {
+		sampledAt := time.Now()
+		s := a.sample(ctx, actx, interval)
+		result.Attempts++
+
+		if s.term > 0 {
+			if firstTerm == 0 {
+				firstTerm = s.term
+			}
+			lastTerm = max(lastTerm, s.term)
+		}
+		if s.leader != "" {
+			if lastLeader != "" && s.leader != lastLeader {
+				changes++
+			}
+			lastLeader = s.leader
+		} else if prev != nil {
+			leaderless += interval
+		}
+
+		if prev == nil || s.leader != prev.leader || s.term != prev.term {
+			leader := s.leader
+			if leader == "" {
+				leader = "none"
+			}
+			timeline = append(timeline, map[string]any{
+				"at":     sampledAt.Sub(start).Round(time.Millisecond).String(),
+				"leader": leader,
+				"term":   s.term,
+				"views":  s.views,
+			})
+		}
+		prev = s
+
+		if !time.Now().Before(deadline) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			result.Duration = time.Since(start)
+			result.Details["timeline"] = timeline
+			result.Message = "Context cancelled"
+			return result, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+	result.Duration = time.Since(start)
+
+	termIncrease := lastTerm - firstTerm
+	result.Details["timeline"] = timeline
+	result.Details["leader_changes"] = changes
+	result.Details["term_increase"] = termIncrease
+	result.Details["leader"] = lastLeader
+	if leaderless > 0 {
+		result.Details["leaderless"] = leaderless.String()
+	}
+
+	var problems []string
+	if lastLeader == "" {
+		problems = append(problems, "no leader observed")
+	}
+	if changes > maxChanges {
+		problems = append(problems, fmt.Sprintf("leader changed %d times (max %d)", changes, maxChanges))
+	}
+	if termIncrease > maxTermIncrease {
+		problems = append(problems, fmt.Sprintf("term rose from %d to %d (max increase %d)", firstTerm, lastTerm, maxTermIncrease))
+	}
+	if len(problems) > 0 {
+		result.Message = fmt.Sprintf("Leadership unstable over %s: %s", duration, strings.Join(problems, "; "))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("Leader %s stable at term %d for %s (%d samples)", lastLeader, lastTerm, duration, result.Attempts)
+	return result, nil
+}
+
+// sample asks every server for its leader and raft state. The leader is
+// the server that reports itself as raft leader at the highest term, or
+// failing that the leader most servers agree on.
+func (a *LeaderStableAssertion) sample(ctx context.Context, actx *driver.AssertContext, timeout time.Duration) *leaderSample {
+	type view struct {
+		server, leader, state string
+		term                  uint64
+		err                   error
+	}
+
+	views := make([]view, len(actx.Cluster.Servers))
+	var wg sync.WaitGroup
+	for i, server := range actx.Cluster.Servers {
+		wg.Add(1)
+		go func(i int, server driver.Node) {
+			defer wg.Done()
+			v := view{server: server.Name}
+			defer func() { views[i] = v }()
+
+			reqCtx, cancel := context.WithTimeout(ctx, max(timeout, 2*time.Second))
+			defer cancel()
+
+			client, err := actx.Driver.NomadClient(server)
+			if err != nil {
+				v.err = err
+				return
+			}
+			addr, err := client.Leader(reqCtx)
+			if err != nil {
+				v.err = err
+				return
+			}
+			if addr != "" {
+				ip := strings.Split(addr, ":")[0]
+				v.leader = ip
+				if n, err := actx.Cluster.NodeByIP(ip); err == nil {
+					v.leader = n.Name
+				}
+			}
+			stats, err := client.RaftStats(reqCtx)
+			if err != nil {
+				v.err = err
+				return
+			}
+			v.state, v.term = stats.State, stats.Term
+		}(i, server)
+	}
+	wg.Wait()
+
+	s := &leaderSample{views: make(map[string]string)}
+	votes := make(map[string]int)
+	var leaderTerm uint64
+	for _, v := range views {
+		if v.err != nil {
+			s.views[v.server] = "error: " + v.err.Error()
+			continue
+		}
+		leader := v.leader
+		if leader == "" {
+			leader = "none"
+		}
+		s.views[v.server] = fmt.Sprintf("%s, leader %s, term %d", strings.ToLower(v.state), leader, v.term)
+
+		s.term = max(s.term, v.term)
+		if v.state == "Leader" && v.term >= leaderTerm {
+			s.leader, leaderTerm = v.server, v.term
+		}
+		if v.leader != "" {
+			votes[v.leader]++
+		}
+	}
+
+	// A leader behind the highest term has been deposed
+	if leaderTerm < s.term {
+		s.leader = ""
+	}
+	if s.leader == "" && len(votes) > 0 {
+		names := make([]string, 0, len(votes))
+		for name := range votes {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool { return votes[names[i]] > votes[names[j]] })
+		s.leader = names[0]
+	}
+	return s
+}
@@ -0,0 +1,256 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// LogPatternAssertion checks how often a pattern appears in a systemd
+// unit's journal on selected nodes.
+type LogPatternAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *LogPatternAssertion) Name() string {
+	return "log-pattern"
+}
+
+// Description returns a human-readable description.
+func (a *LogPatternAssertion) Description() string {
+	return "Verify that journal lines matching a pattern appear between min and max times on selected nodes"
+}
+
+// Check reads `journalctl -u <unit>` since the start of the window from
+// every selected node (default all) and counts the lines that match any
+// include regex and no exclude regex. The total across nodes must be at
+// least min (default 0) and at most max (default 0 when min is 0, so any
+// match fails; otherwise unlimited). Matching lines are attached to the
+// report. With within set the journal is read again until the counts are
+// met, which suits waiting for a line to appear.
+func (a *LogPatternAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	unit := params.String(args, "unit", "nomad")
+	include, err := compilePatterns(rawList(args, "include"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid include: %w", err)
+	}
+	if len(include) == 0 {
+		return nil, fmt.Errorf("include is required")
+	}
+	exclude, err := compilePatterns(rawList(args, "exclude"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude: %w", err)
+	}
+	minCount, maxCount, err := matchLimits(args)
+	if err != nil {
+		return nil, err
+	}
+	maxLines := params.Int(args, "max_lines", 50)
+	since, sinceDesc, err := logSince(actx, args)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := params.Duration(args, "within", 0)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := params.Duration(args, "interval", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	selectors := params.StringSlice(args, "nodes")
+	if len(selectors) == 0 {
+		selectors = []string{"all"}
+	}
+	nodes, err := driver.SelectNodes(ctx, actx.Driver, actx.Cluster, selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["unit"] = unit
+	result.Details["since"] = since.Format(time.RFC3339)
+	result.Details["window"] = sinceDesc
+	result.Details["min"] = minCount
+	if maxCount >= 0 {
+		result.Details["max"] = maxCount
+	}
+	if timeout > 0 {
+		result.Details["timeout"] = timeout.String()
+	}
+
+	var total int
+	var counts map[string]int
+	var matches []string
+	var problems []string
+	ok, err := eventually(ctx, result, timeout, interval, func() bool {
+		total, counts, matches, problems = 0, make(map[string]int), nil, nil
+		cmd := journalCmd(unit, since, time.Now())
+
+		type nodeLog struct {
+			lines []string
+			err   error
+		}
+		logs := make([]nodeLog, len(nodes))
+		var wg sync.WaitGroup
+		for i, node := range nodes {
+			wg.Add(1)
+			go func(i int, node driver.Node) {
+				defer wg.Done()
+				out, err := runOnNode(ctx, actx, node, cmd)
+				logs[i] = nodeLog{lines: strings.Split(out, "\n"), err: err}
+			}(i, node)
+		}
+		wg.Wait()
+
+		for i, node := range nodes {
+			if logs[i].err != nil {
+				problems = append(problems, fmt.Sprintf("%s: reading journal: %v", node.Name, logs[i].err))
+				continue
+			}
+			for _, line := range logs[i].lines {
+				if line == "" || strings.HasPrefix(line, "-- ") || !matchesAny(include, line) || matchesAny(exclude, line) {
+					continue
+				}
+				counts[node.Name]++
+				total++
+				if len(matches) < maxLines {
+					matches = append(matches, node.Name+": "+line)
+				}
+			}
+		}
+		// A node whose journal could not be read leaves the count unknown
+		return len(problems) == 0 && total >= minCount && (maxCount < 0 || total <= maxCount)
+	})
+	result.Details["total"] = total
+	result.Details["counts"] = counts
+	if len(matches) > 0 {
+		result.Details["matches"] = matches
+	}
+	if total > len(matches) {
+		result.Details["matches_omitted"] = total - len(matches)
+	}
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	if !ok {
+		switch {
+		case len(problems) > 0:
+			sort.Strings(problems)
+			result.Details["problems"] = problems
+			result.Message = strings.Join(problems, "; ")
+		case total < minCount:
+			result.Message = fmt.Sprintf("%d %s journal lines matched since %s, want at least %d", total, unit, sinceDesc, minCount)
+		default:
+			result.Message = fmt.Sprintf("%d %s journal lines matched since %s, want at most %d", total, unit, sinceDesc, maxCount)
+		}
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d %s journal lines matched since %s across %d nodes", total, unit, sinceDesc, len(nodes))
+	return result, nil
+}
+
+// logSince resolves the since arg to the start of the log window and a
+// description of it. since is a duration to look back, an RFC 3339 time,
+// "fault" for the start of the latest action step or "scenario" for the
+// start of the scenario. By default it is the latest action step, then
+// the scenario start, then the last 5 minutes.
+func logSince(actx *driver.AssertContext, args map[string]any) (time.Time, string, error) {
+	fault := func() (time.Time, bool) {
+		for i := len(actx.History) - 1; i >= 0; i-- {
+			if t := actx.History[i].StartedAt; !t.IsZero() {
+				return t, true
+			}
+		}
+		return time.Time{}, false
+	}
+
+	switch s := params.String(args, "since", ""); s {
+	case "":
+		if t, ok := fault(); ok {
+			return t, "the last action", nil
+		}
+		if !actx.ScenarioStart.IsZero() {
+			return actx.ScenarioStart, "the scenario start", nil
+		}
+		return time.Now().Add(-5 * time.Minute), "5m ago", nil
+	case "fault":
+		t, ok := fault()
+		if !ok {
+			return time.Time{}, "", fmt.Errorf("since fault: no action earlier in this run")
+		}
+		return t, "the last action", nil
+	case "scenario":
+		if actx.ScenarioStart.IsZero() {
+			return time.Time{}, "", fmt.Errorf("since scenario: not running in a scenario")
+		}
+		return actx.ScenarioStart, "the scenario start", nil
+	default:
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t, s, nil
+		}
+		d, err := params.Duration(args, "since", 0)
+		if err != nil {
+			return time.Time{}, "", fmt.Errorf("invalid since %q: want a duration, RFC 3339 time, fault or scenario", s)
+		}
+		return time.Now().Add(-d), d.String() + " ago", nil
+	}
+}
+
+// matchLimits reads the min and max args. max is -1 for no limit; it
+// defaults to 0 when min is 0, so that any match fails.
+func matchLimits(args map[string]any) (int, int, error) {
+	minCount := params.Int(args, "min", 0)
+	maxCount := params.Int(args, "max", -1)
+	if _, ok := args["max"]; !ok && minCount == 0 {
+		maxCount = 0
+	}
+	if maxCount >= 0 && maxCount < minCount {
+		return 0, 0, fmt.Errorf("max %d is below min %d", maxCount, minCount)
+	}
+	return minCount, maxCount, nil
+}
+
+// journalCmd reads unit's journal from since onwards. The window is given
+// relative to the node's clock, rounded up to whole seconds, so that a
+// node whose clock is off (say after clock-skew) still reads the lines
+// logged since then rather than an absolute time it disagrees on.
+func journalCmd(unit string, since, now time.Time) string {
+	secs := int64(math.Ceil(now.Sub(since).Seconds()))
+	return fmt.Sprintf("journalctl -u %s --since -%ds --no-pager -o short-iso", shellQuote(unit), max(secs, 0))
+}
+
+// compilePatterns compiles a list of regexes.
+func compilePatterns(exprs []string) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(exprs))
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns, nil
+}
+
+// matchesAny reports whether line matches any of the patterns.
+func matchesAny(patterns []*regexp.Regexp, line string) bool {
+	for _, re := range patterns {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
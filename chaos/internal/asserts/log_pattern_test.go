@@ -0,0 +1,161 @@
+package asserts
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+)
+
+func TestLogSince(t *testing.T) {
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	fault := start.Add(2 * time.Minute)
+	inScenario := &driver.AssertContext{
+		ScenarioStart: start,
+		History: []*driver.ActionContext{
+			{StartedAt: start.Add(time.Minute)},
+			{StartedAt: fault},
+			{}, // an action that never started
+		},
+	}
+	standalone := &driver.AssertContext{}
+
+	tests := []struct {
+		name  string
+		actx  *driver.AssertContext
+		since any
+		want  time.Time
+		desc  string
+	}{
+		{"default is the last action", inScenario, nil, fault, "the last action"},
+		{"default without actions", &driver.AssertContext{ScenarioStart: start}, nil, start, "the scenario start"},
+		{"fault", inScenario, "fault", fault, "the last action"},
+		{"scenario", inScenario, "scenario", start, "the scenario start"},
+		{"RFC 3339", standalone, "2026-01-02T09:00:00Z", start.Add(-time.Hour), "2026-01-02T09:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{}
+			if tt.since != nil {
+				args["since"] = tt.since
+			}
+			got, desc, err := logSince(tt.actx, args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) || desc != tt.desc {
+				t.Errorf("logSince = %s, %q, want %s, %q", got, desc, tt.want, tt.desc)
+			}
+		})
+	}
+
+	got, desc, err := logSince(standalone, map[string]any{"since": "10m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ago := time.Since(got); ago < 10*time.Minute || ago > 11*time.Minute || desc != "10m0s ago" {
+		t.Errorf("since 10m = %s ago, %q", ago, desc)
+	}
+	if got, _, _ := logSince(standalone, map[string]any{}); time.Since(got) < 5*time.Minute {
+		t.Errorf("default outside a scenario = %s ago, want 5m", time.Since(got))
+	}
+
+	for _, since := range []string{"fault", "scenario", "yesterday"} {
+		if _, _, err := logSince(standalone, map[string]any{"since": since}); err == nil {
+			t.Errorf("logSince(%q) outside a scenario succeeded", since)
+		}
+	}
+}
+
+func TestJournalCmd(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tests := []struct {
+		since time.Time
+		want  string
+	}{
+		{now.Add(-90 * time.Second), "journalctl -u 'nomad' --since -90s --no-pager -o short-iso"},
+		{now.Add(-1500 * time.Millisecond), "journalctl -u 'nomad' --since -2s --no-pager -o short-iso"},
+		{now.Add(time.Second), "journalctl -u 'nomad' --since -0s --no-pager -o short-iso"},
+	}
+	for _, tt := range tests {
+		if got := journalCmd("nomad", tt.since, now); got != tt.want {
+			t.Errorf("journalCmd(%s) = %q, want %q", now.Sub(tt.since), got, tt.want)
+		}
+	}
+}
+
+func TestMatchLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     map[string]any
+		min, max int
+	}{
+		{"no limits means no match", map[string]any{}, 0, 0},
+		{"min only is unbounded", map[string]any{"min": 2}, 2, -1},
+		{"explicit max", map[string]any{"max": 5}, 0, 5},
+		{"both", map[string]any{"min": 1, "max": 3}, 1, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lo, hi, err := matchLimits(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lo != tt.min || hi != tt.max {
+				t.Errorf("matchLimits = %d, %d, want %d, %d", lo, hi, tt.min, tt.max)
+			}
+		})
+	}
+
+	if _, _, err := matchLimits(map[string]any{"min": 3, "max": 1}); err == nil {
+		t.Error("matchLimits accepted max below min")
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	patterns, err := compilePatterns([]string{`(?i)panic`, `leader.{0,3}lost`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for line, want := range map[string]bool{
+		"PANIC: runtime error": true,
+		"raft: leader is lost": false,
+		"raft: leader lost":    true,
+		"heartbeat ok":         false,
+	} {
+		if got := matchesAny(patterns, line); got != want {
+			t.Errorf("matchesAny(%q) = %v, want %v", line, got, want)
+		}
+	}
+	if matchesAny(nil, "anything") {
+		t.Error("matchesAny with no patterns matched")
+	}
+	if _, err := compilePatterns([]string{"ok", "("}); err == nil {
+		t.Error("compilePatterns accepted an invalid regex")
+	}
+}
+
+func TestRawList(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want []string
+	}{
+		{"string with commas", "a{1,3}, b", []string{"a{1,3}, b"}},
+		{"blank string", "  ", nil},
+		{"list", []any{"a,b", "c"}, []string{"a,b", "c"}},
+		{"missing", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{}
+			if tt.v != nil {
+				args["include"] = tt.v
+			}
+			if got := rawList(args, "include"); !slices.Equal(got, tt.want) {
+				t.Errorf("rawList = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/metrics"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// MetricAssertion checks a Nomad metric scraped from the agents'
+// Prometheus endpoint against a threshold.
+type MetricAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *MetricAssertion) Name() string {
+	return "metric"
+}
+
+// Description returns a human-readable description.
+func (a *MetricAssertion) Description() string {
+	return "Verify a Nomad Prometheus metric, or its rate over a window, against a threshold on selected nodes"
+}
+
+// Check scrapes /v1/metrics?format=prometheus on every selected node
+// (default servers), keeps the series of the metric that satisfy every
+// labels matcher and aggregates them per node (sum, max, min, avg or
+// count). A single labels string is one matcher and is not split on
+// commas, so regexes like "x{1,3}" stay whole. With rate set each node is
+// scraped twice, rate apart, and the per-second increase of each series is
+// aggregated instead. The check passes when the nodes required by require
+// (all, any or a count) meet the threshold, e.g. "< 200"; with within set
+// it is retried until then.
+func (a *MetricAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	name := params.String(args, "metric", "")
+	if name == "" {
+		return nil, fmt.Errorf("metric is required")
+	}
+	var matchers []metrics.Matcher
+	for _, s := range rawList(args, "labels") {
+		m, err := metrics.ParseMatcher(s)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	aggregate := params.String(args, "aggregate", "sum")
+	switch aggregate {
+	case "sum", "max", "min", "avg", "count":
+	default:
+		return nil, fmt.Errorf("invalid aggregate %q: must be sum, max, min, avg or count", aggregate)
+	}
+	var limit *threshold
+	if s := params.String(args, "threshold", ""); s != "" {
+		t, err := parseThreshold(s)
+		if err != nil {
+			return nil, err
+		}
+		limit = t
+	}
+	window, err := params.Duration(args, "rate", 0)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := params.Duration(args, "within", 0)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := params.Duration(args, "interval", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	require := params.String(args, "require", "all")
+
+	selectors := params.StringSlice(args, "nodes")
+	if len(selectors) == 0 {
+		selectors = []string{"servers"}
+	}
+	nodes, err := driver.SelectNodes(ctx, actx.Driver, actx.Cluster, selectors)
+	if err != nil {
+		return nil, err
+	}
+	need, err := requiredPasses(require, len(nodes))
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["metric"] = name
+	result.Details["aggregate"] = aggregate
+	if limit != nil {
+		result.Details["threshold"] = limit.String()
+	}
+	if window > 0 {
+		result.Details["rate_window"] = window.String()
+	}
+	if timeout > 0 {
+		result.Details["timeout"] = timeout.String()
+	}
+
+	var outcomes map[string]map[string]any
+	var failing []string
+	ok, err := eventually(ctx, result, timeout, interval, func() bool {
+		outcomes = make(map[string]map[string]any, len(nodes))
+		failing = failing[:0]
+
+		first := scrapeNodes(ctx, actx, nodes)
+		second := first
+		if window > 0 {
+			select {
+			case <-ctx.Done():
+				return false
+			case <-time.After(window):
+			}
+			second = scrapeNodes(ctx, actx, nodes)
+		}
+
+		for i, node := range nodes {
+			outcome := map[string]any{"passed": false}
+			outcomes[node.Name] = outcome
+
+			values, err := seriesValues(first[i], second[i], name, matchers, window > 0)
+			if err != nil {
+				outcome["reason"] = err.Error()
+				failing = append(failing, fmt.Sprintf("%s: %v", node.Name, err))
+				continue
+			}
+			outcome["series"] = len(values)
+
+			v, ok := aggregateValues(values, aggregate)
+			if !ok {
+				outcome["reason"] = "no matching series"
+				failing = append(failing, node.Name+": no matching series")
+				continue
+			}
+			outcome["value"] = v
+			if limit != nil && !limit.holds(v) {
+				reason := fmt.Sprintf("%s is not %s", strconv.FormatFloat(v, 'g', 6, 64), limit)
+				outcome["reason"] = reason
+				failing = append(failing, node.Name+": "+reason)
+				continue
+			}
+			outcome["passed"] = true
+		}
+		sort.Strings(failing)
+		return len(nodes)-len(failing) >= need
+	})
+	result.Details["nodes"] = outcomes
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	subject := name
+	if window > 0 {
+		subject = fmt.Sprintf("rate(%s[%s])", name, window)
+	}
+	passed := len(nodes) - len(failing)
+	if !ok {
+		result.Message = fmt.Sprintf("%s: %d/%d nodes passed, %d required: %s", subject, passed, len(nodes), need, strings.Join(failing, "; "))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%s: %d/%d nodes passed (%d required)", subject, passed, len(nodes), need)
+	return result, nil
+}
+
+// scrape is one node's metrics at a point in time.
+type scrape struct {
+	samples []metrics.Sample
+	at      time.Time
+	err     error
+}
+
+// scrapeNodes fetches and parses the metrics of every node concurrently.
+func scrapeNodes(ctx context.Context, actx *driver.AssertContext, nodes []driver.Node) []scrape {
+	scrapes := make([]scrape, len(nodes))
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		wg.Add(1)
+		go func(i int, node driver.Node) {
+			defer wg.Done()
+			s := &scrapes[i]
+			client, err := actx.Driver.NomadClient(node)
+			if err != nil {
+				s.err = err
+				return
+			}
+			data, err := client.PrometheusMetrics(ctx)
+			s.at = time.Now()
+			if err != nil {
+				s.err = fmt.Errorf("scraping metrics: %w", err)
+				return
+			}
+			if s.samples, err = metrics.Parse(data); err != nil {
+				s.err = fmt.Errorf("parsing metrics: %w", err)
+			}
+		}(i, node)
+	}
+	wg.Wait()
+	return scrapes
+}
+
+// seriesValues returns the value of each matching series in the latest
+// scrape, or with rate its per-second increase since the first scrape.
+// A counter that went down was reset, so its whole value is the increase.
+func seriesValues(first, second scrape, name string, matchers []metrics.Matcher, rate bool) ([]float64, error) {
+	if second.err != nil {
+		return nil, second.err
+	}
+	latest := metrics.Select(second.samples, name, matchers)
+	if !rate {
+		values := make([]float64, len(latest))
+		for i, s := range latest {
+			values[i] = s.Value
+		}
+		return values, nil
+	}
+
+	if first.err != nil {
+		return nil, first.err
+	}
+	elapsed := second.at.Sub(first.at).Seconds()
+	if elapsed <= 0 {
+		return nil, fmt.Errorf("scrapes are not apart in time")
+	}
+	before := make(map[string]float64)
+	for _, s := range metrics.Select(first.samples, name, matchers) {
+		before[s.Key()] = s.Value
+	}
+	var values []float64
+	for _, s := range latest {
+		prev, ok := before[s.Key()]
+		if !ok {
+			continue
+		}
+		delta := s.Value - prev
+		if delta < 0 {
+			delta = s.Value
+		}
+		values = append(values, delta/elapsed)
+	}
+	return values, nil
+}
+
+// aggregateValues combines series values. It reports false when there is
+// nothing to aggregate, except for count, which is then zero.
+func aggregateValues(values []float64, how string) (float64, bool) {
+	if how == "count" {
+		return float64(len(values)), true
+	}
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	switch how {
+	case "max":
+		v := math.Inf(-1)
+		for _, x := range values {
+			v = math.Max(v, x)
+		}
+		return v, true
+	case "min":
+		v := math.Inf(1)
+		for _, x := range values {
+			v = math.Min(v, x)
+		}
+		return v, true
+	}
+
+	var sum float64
+	for _, x := range values {
+		sum += x
+	}
+	if how == "avg" {
+		return sum / float64(len(values)), true
+	}
+	return sum, true
+}
+
+// threshold is a comparison such as "< 200".
+type threshold struct {
+	op    string
+	value float64
+}
+
+// parseThreshold parses an operator (<, <=, >, >=, == or !=) followed by
+// a number.
+func parseThreshold(s string) (*threshold, error) {
+	s = strings.TrimSpace(s)
+	for _, op := range []string{"<=", ">=", "==", "!=", "<", ">"} {
+		if !strings.HasPrefix(s, op) {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(s[len(op):]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold %q: %w", s, err)
+		}
+		return &threshold{op: op, value: v}, nil
+	}
+	return nil, fmt.Errorf("invalid threshold %q: must start with <, <=, >, >=, == or !=", s)
+}
+
+// holds reports whether v meets the threshold.
+func (t *threshold) holds(v float64) bool {
+	switch t.op {
+	case "<":
+		return v < t.value
+	case "<=":
+		return v <= t.value
+	case ">":
+		return v > t.value
+	case ">=":
+		return v >= t.value
+	case "==":
+		return v == t.value
+	default:
+		return v != t.value
+	}
+}
+
+// String returns the threshold as written.
+func (t *threshold) String() string {
+	return t.op + " " + strconv.FormatFloat(t.value, 'g', -1, 64)
+}
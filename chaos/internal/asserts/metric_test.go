@@ -0,0 +1,129 @@
+package asserts
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/metrics"
+)
+
+func TestParseThreshold(t *testing.T) {
+	tests := []struct {
+		in    string
+		v     float64
+		holds bool
+		str   string
+	}{
+		{"< 200", 150, true, "< 200"},
+		{"<200", 200, false, "< 200"},
+		{"<= 200", 200, true, "<= 200"},
+		{" > 0.5 ", 0.5, false, "> 0.5"},
+		{">= 1e3", 1000, true, ">= 1000"},
+		{"== 3", 3, true, "== 3"},
+		{"!= 3", 3, false, "!= 3"},
+	}
+	for _, tt := range tests {
+		th, err := parseThreshold(tt.in)
+		if err != nil {
+			t.Errorf("parseThreshold(%q): %v", tt.in, err)
+			continue
+		}
+		if got := th.holds(tt.v); got != tt.holds {
+			t.Errorf("%q holds(%v) = %v, want %v", tt.in, tt.v, got, tt.holds)
+		}
+		if got := th.String(); got != tt.str {
+			t.Errorf("parseThreshold(%q).String() = %q, want %q", tt.in, got, tt.str)
+		}
+	}
+
+	for _, in := range []string{"", "200", "< fast", "=~ 3"} {
+		if _, err := parseThreshold(in); err == nil {
+			t.Errorf("parseThreshold(%q) succeeded", in)
+		}
+	}
+}
+
+func TestAggregateValues(t *testing.T) {
+	values := []float64{4, 1, 7}
+	tests := []struct {
+		how  string
+		want float64
+	}{
+		{"sum", 12},
+		{"max", 7},
+		{"min", 1},
+		{"avg", 4},
+		{"count", 3},
+	}
+	for _, tt := range tests {
+		got, ok := aggregateValues(values, tt.how)
+		if !ok || got != tt.want {
+			t.Errorf("aggregateValues(%s) = %v, %v, want %v", tt.how, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := aggregateValues(nil, "sum"); ok {
+		t.Error("aggregateValues of no series reported a value")
+	}
+	if got, ok := aggregateValues(nil, "count"); !ok || got != 0 {
+		t.Errorf("count of no series = %v, %v, want 0, true", got, ok)
+	}
+}
+
+func TestSeriesValues(t *testing.T) {
+	sample := func(method string, v float64) metrics.Sample {
+		return metrics.Sample{Name: "rpc", Labels: map[string]string{"method": method}, Value: v}
+	}
+	start := time.Unix(1000, 0)
+	first := scrape{at: start, samples: []metrics.Sample{
+		sample("a", 10),
+		sample("b", 100),
+	}}
+	second := scrape{at: start.Add(10 * time.Second), samples: []metrics.Sample{
+		sample("a", 30),
+		sample("b", 20),
+		sample("c", 5),
+	}}
+
+	got, err := seriesValues(first, second, "rpc", nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{30, 20, 5}; !slices.Equal(got, want) {
+		t.Errorf("values = %v, want %v", got, want)
+	}
+
+	// b was reset, so its whole value counts; c is new and has no rate yet.
+	got, err = seriesValues(first, second, "rpc", nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{2, 2}; !slices.Equal(got, want) {
+		t.Errorf("rates = %v, want %v", got, want)
+	}
+
+	m, err := metrics.ParseMatcher("method=a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = seriesValues(first, second, "rpc", []metrics.Matcher{m}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{2}; !slices.Equal(got, want) {
+		t.Errorf("matched rates = %v, want %v", got, want)
+	}
+
+	if _, err := seriesValues(first, first, "rpc", nil, true); err == nil {
+		t.Error("rate over simultaneous scrapes succeeded")
+	}
+	failed := scrape{err: errors.New("connection refused")}
+	if _, err := seriesValues(first, failed, "rpc", nil, false); err == nil {
+		t.Error("values from a failed scrape succeeded")
+	}
+	if _, err := seriesValues(failed, second, "rpc", nil, true); err == nil {
+		t.Error("rate from a failed first scrape succeeded")
+	}
+}
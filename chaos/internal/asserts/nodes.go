@@ -0,0 +1,227 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// NodesReadyAssertion checks that client nodes are ready and eligible.
+type NodesReadyAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *NodesReadyAssertion) Name() string {
+	return "nodes-ready"
+}
+
+// Description returns a human-readable description.
+func (a *NodesReadyAssertion) Description() string {
+	return "Verify that client nodes are ready, eligible and not draining"
+}
+
+// Check polls the selected client nodes until at least count of them
+// match and every one is ready, eligible (unless eligible=false) and not
+// draining. count defaults to the named nodes when nodes is given, to the
+// discovered clients when no filter is given, and to one otherwise.
+func (a *NodesReadyAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	timeout, err := params.Duration(args, "within", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := parseNodeFilter(args)
+	if err != nil {
+		return nil, err
+	}
+	eligible := params.Bool(args, "eligible", true)
+	count := params.Int(args, "count", filter.defaultCount(len(actx.Cluster.Clients)))
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+	result.Details["min_count"] = count
+	if !filter.empty() {
+		result.Details["filter"] = filter.String()
+	}
+
+	var statuses map[string]string
+	var problems []string
+	ok, err := eventually(ctx, result, timeout, 2*time.Second, func() bool {
+		statuses = make(map[string]string)
+		problems = nil
+
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			problems = []string{err.Error()}
+			return false
+		}
+		nodes, err := selectClientNodes(ctx, actx, client, filter)
+		if err != nil {
+			problems = []string{err.Error()}
+			return false
+		}
+
+		for _, n := range nodes {
+			status := fmt.Sprintf("%s, %s", n.Status, n.SchedulingEligibility)
+			if n.DrainStrategy != nil {
+				status += ", draining"
+			}
+			if n.StatusDescription != "" {
+				status += ": " + n.StatusDescription
+			}
+			statuses[n.Name] = status
+
+			switch {
+			case n.Status != "ready":
+				problems = append(problems, fmt.Sprintf("%s is %s", n.Name, n.Status))
+			case n.DrainStrategy != nil:
+				problems = append(problems, fmt.Sprintf("%s is draining", n.Name))
+			case eligible && n.SchedulingEligibility != nomad.NodeEligible:
+				problems = append(problems, fmt.Sprintf("%s is %s", n.Name, n.SchedulingEligibility))
+			}
+		}
+		if len(nodes) < count {
+			problems = append(problems, fmt.Sprintf("%d nodes match, want at least %d", len(nodes), count))
+		}
+		return len(problems) == 0
+	})
+	result.Details["nodes"] = statuses
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	if !ok {
+		result.Details["problems"] = problems
+		result.Message = fmt.Sprintf("Nodes not ready within %s: %s", timeout, strings.Join(problems, "; "))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("All %d nodes ready", len(statuses))
+	return result, nil
+}
+
+// nodeFilter selects client nodes by name, class, datacenter and labels.
+type nodeFilter struct {
+	names      []string // discovered names, Nomad names or IDs
+	class      string
+	datacenter string
+	labels     map[string]string // matched against node meta, then attributes
+}
+
+// defaultCount is how many nodes must match when no count is given: every
+// named node, every discovered client without a filter, else at least one.
+func (f nodeFilter) defaultCount(clients int) int {
+	switch {
+	case len(f.names) > 0:
+		return len(f.names)
+	case f.empty():
+		return max(clients, 1)
+	default:
+		return 1
+	}
+}
+
+// parseNodeFilter reads the nodes, class, datacenter and labels
+// (key=value,...) args.
+func parseNodeFilter(args map[string]any) (nodeFilter, error) {
+	f := nodeFilter{
+		names:      params.StringSlice(args, "nodes"),
+		class:      params.String(args, "class", ""),
+		datacenter: params.String(args, "datacenter", ""),
+	}
+	for _, label := range params.StringSlice(args, "labels") {
+		key, value, ok := strings.Cut(label, "=")
+		if !ok {
+			return f, fmt.Errorf("invalid label %q: want key=value", label)
+		}
+		if f.labels == nil {
+			f.labels = make(map[string]string)
+		}
+		f.labels[key] = value
+	}
+	return f, nil
+}
+
+// empty reports whether the filter selects every client node.
+func (f nodeFilter) empty() bool {
+	return len(f.names) == 0 && f.class == "" && f.datacenter == "" && len(f.labels) == 0
+}
+
+// String describes the filter for details.
+func (f nodeFilter) String() string {
+	var parts []string
+	if len(f.names) > 0 {
+		parts = append(parts, "nodes="+strings.Join(f.names, ","))
+	}
+	if f.class != "" {
+		parts = append(parts, "class="+f.class)
+	}
+	if f.datacenter != "" {
+		parts = append(parts, "datacenter="+f.datacenter)
+	}
+	keys := make([]string, 0, len(f.labels))
+	for k := range f.labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, k+"="+f.labels[k])
+	}
+	return strings.Join(parts, " ")
+}
+
+// matchLabels reports whether every label is set on the node's meta or
+// attributes.
+func (f nodeFilter) matchLabels(n *nomad.Node) bool {
+	for k, v := range f.labels {
+		got, ok := n.Meta[k]
+		if !ok {
+			got, ok = n.Attributes[k]
+		}
+		if !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// selectClientNodes reads the Nomad client nodes matching the filter.
+// Names may be discovered names (client-0), Nomad node names or IDs.
+func selectClientNodes(ctx context.Context, actx *driver.AssertContext, client *nomad.Client, f nodeFilter) ([]*nomad.Node, error) {
+	stubs, err := client.Nodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	want := make(map[string]bool)
+	for _, name := range f.names {
+		want[name] = true
+		if n, err := actx.Cluster.NodeByName(name); err == nil {
+			want[n.PrivateIP] = true
+		}
+	}
+
+	var nodes []*nomad.Node
+	for _, s := range stubs {
+		if len(want) > 0 && !want[s.Name] && !want[s.ID] && !want[s.Address] {
+			continue
+		}
+		if f.class != "" && s.NodeClass != f.class || f.datacenter != "" && s.Datacenter != f.datacenter {
+			continue
+		}
+		node, err := client.Node(ctx, s.ID)
+		if err != nil {
+			return nil, fmt.Errorf("reading node %s: %w", s.Name, err)
+		}
+		if f.matchLabels(node) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
@@ -0,0 +1,34 @@
+package asserts
+
+import "testing"
+
+func TestNodeFilterDefaultCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]any
+		clients int
+		want    int
+	}{
+		{"no filter", map[string]any{}, 3, 3},
+		{"no filter or clients", map[string]any{}, 0, 1},
+		{"named nodes", map[string]any{"nodes": "client-0,client-1"}, 3, 2},
+		{"named nodes with class", map[string]any{"nodes": []any{"client-0"}, "class": "virt"}, 3, 1},
+		{"class", map[string]any{"class": "virt"}, 3, 1},
+		{"labels", map[string]any{"labels": "zone=a"}, 3, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := parseNodeFilter(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f.defaultCount(tt.clients); got != tt.want {
+				t.Errorf("defaultCount(%d) = %d, want %d", tt.clients, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseNodeFilter(map[string]any{"labels": "zone"}); err == nil {
+		t.Error("parseNodeFilter accepted a label without =")
+	}
+}
@@ -0,0 +1,570 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// HTTPProbeAssertion checks that HTTP endpoints answer as expected.
+type HTTPProbeAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *HTTPProbeAssertion) Name() string {
+	return "http-probe"
+}
+
+// Description returns a human-readable description.
+func (a *HTTPProbeAssertion) Description() string {
+	return "Probe HTTP endpoints from URLs, job allocations or Consul services for status, body and latency"
+}
+
+// Check probes the endpoints eventually (until they pass) or consistently
+// (for a whole window, reporting any outages).
+func (a *HTTPProbeAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	return runProbe(ctx, actx, a.Name(), "http", args)
+}
+
+// TCPProbeAssertion checks that TCP endpoints accept connections.
+type TCPProbeAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *TCPProbeAssertion) Name() string {
+	return "tcp-probe"
+}
+
+// Description returns a human-readable description.
+func (a *TCPProbeAssertion) Description() string {
+	return "Probe TCP endpoints from addresses, job allocations or Consul services for connects and latency"
+}
+
+// Check probes the endpoints eventually (until they pass) or consistently
+// (for a whole window, reporting any outages).
+func (a *TCPProbeAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	return runProbe(ctx, actx, a.Name(), "tcp", args)
+}
+
+// endpoint is one probe target.
+type endpoint struct {
+	host string
+	port int
+	url  string       // http only
+	node *driver.Node // discovered node the endpoint is on, if any
+}
+
+// String identifies the endpoint in details.
+func (e endpoint) String() string {
+	if e.url != "" {
+		return e.url
+	}
+	return net.JoinHostPort(e.host, strconv.Itoa(e.port))
+}
+
+// prober holds the parsed probe arguments.
+type prober struct {
+	kind       string // http or tcp
+	from       string // local, host or a node name
+	timeout    time.Duration
+	maxLatency time.Duration
+	statuses   []int
+	body       *regexp.Regexp
+	path       string
+	scheme     string
+}
+
+// runProbe implements http-probe and tcp-probe. Endpoints are resolved
+// again every round, so allocations replaced during the window are
+// followed; a round whose resolution fails probes the last endpoints
+// resolved instead.
+func runProbe(ctx context.Context, actx *driver.AssertContext, name, kind string, args map[string]any) (*Result, error) {
+	mode := params.String(args, "mode", "eventually")
+	if mode != "eventually" && mode != "consistently" {
+		return nil, fmt.Errorf("invalid mode %q: must be eventually or consistently", mode)
+	}
+	require := params.String(args, "require", "all")
+	if require != "all" && require != "any" {
+		return nil, fmt.Errorf("invalid require %q: must be all or any", require)
+	}
+	interval, err := params.Duration(args, "interval", time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &prober{
+		kind:   kind,
+		path:   params.String(args, "path", "/"),
+		scheme: params.String(args, "scheme", "http"),
+	}
+	if p.timeout, err = params.Duration(args, "timeout", 5*time.Second); err != nil {
+		return nil, err
+	}
+	if p.maxLatency, err = params.Duration(args, "max_latency", 0); err != nil {
+		return nil, err
+	}
+	for _, s := range params.StringSlice(args, "status") {
+		code, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status %q", s)
+		}
+		p.statuses = append(p.statuses, code)
+	}
+	if expr := params.String(args, "body", ""); expr != "" {
+		if p.body, err = regexp.Compile(expr); err != nil {
+			return nil, fmt.Errorf("invalid body regex: %w", err)
+		}
+	}
+
+	explicit := params.StringSlice(args, "urls")
+	if kind == "tcp" {
+		explicit = params.StringSlice(args, "addresses")
+	}
+	p.from = params.String(args, "from", "")
+	if p.from == "" {
+		p.from = "host"
+		if len(explicit) > 0 {
+			p.from = "local"
+		}
+	}
+
+	result := NewResult(name, false, "")
+	result.Details["mode"] = mode
+	result.Details["from"] = p.from
+	if p.maxLatency > 0 {
+		result.Details["max_latency"] = p.maxLatency.String()
+	}
+
+	res := &resolver{resolve: func() ([]endpoint, error) {
+		return resolveEndpoints(ctx, actx, args, kind, explicit, p.path, p.scheme)
+	}}
+	defer res.report(result)
+
+	var outcomes map[string]string
+	var maxSeen time.Duration
+	round := func() (bool, string) {
+		endpoints, err := res.endpoints()
+		outcomes = make(map[string]string)
+		if err != nil {
+			return false, err.Error()
+		}
+		if len(endpoints) == 0 {
+			return false, "no endpoints found"
+		}
+
+		passed := 0
+		for _, e := range endpoints {
+			latency, err := p.probe(ctx, actx, e)
+			if err == nil {
+				maxSeen = max(maxSeen, latency)
+				passed++
+				outcomes[e.String()] = "ok in " + latency.Round(time.Millisecond).String()
+			} else {
+				outcomes[e.String()] = err.Error()
+			}
+		}
+		if passed == len(endpoints) || require == "any" && passed > 0 {
+			return true, ""
+		}
+		return false, fmt.Sprintf("%d/%d endpoints failing", len(endpoints)-passed, len(endpoints))
+	}
+
+	if mode == "eventually" {
+		timeout, err := params.Duration(args, "within", time.Minute)
+		if err != nil {
+			return nil, err
+		}
+		result.Details["timeout"] = timeout.String()
+
+		var reason string
+		ok, err := eventually(ctx, result, timeout, interval, func() bool {
+			var ok bool
+			ok, reason = round()
+			return ok
+		})
+		result.Details["endpoints"] = outcomes
+		if err != nil {
+			result.Message = "Context cancelled"
+			return result, err
+		}
+		if !ok {
+			result.Message = fmt.Sprintf("Endpoints not passing within %s: %s", timeout, reason)
+			return result, nil
+		}
+		result.Success = true
+		result.Message = fmt.Sprintf("%d endpoints passing (max latency %s)", len(outcomes), maxSeen.Round(time.Millisecond))
+		return result, nil
+	}
+
+	window, err := params.Duration(args, "duration", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	maxFailures := params.Int(args, "max_failures", 0)
+	result.Details["duration"] = window.String()
+	result.Details["max_failures"] = maxFailures
+
+	var outages []map[string]any
+	var outageStart time.Time
+	var longest time.Duration
+	failed := 0
+	endOutage := func(at time.Time, start time.Time) {
+		d := at.Sub(outageStart)
+		longest = max(longest, d)
+		outages = append(outages, map[string]any{
+			"start":    outageStart.Sub(start).Round(time.Millisecond).String(),
+			"duration": d.Round(time.Millisecond).String(),
+		})
+		outageStart = time.Time{}
+	}
+
+	start := time.Now()
+	deadline := start.Add(window)
+	for {
+		at := time.Now()
+		result.Attempts++
+		if ok, reason := round(); ok {
+			if !outageStart.IsZero() {
+				endOutage(at, start)
+			}
+		} else {
+			failed++
+			if outageStart.IsZero() {
+				outageStart = at
+			}
+			if _, seen := result.Details["first_failure"]; !seen {
+				result.Details["first_failure"] = reason
+				result.Details["first_failure_endpoints"] = outcomes
+			}
+		}
+
+		if !time.Now().Before(deadline) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			result.Duration = time.Since(start)
+			result.Message = "Context cancelled"
+			return result, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+	result.Duration = time.Since(start)
+	if !outageStart.IsZero() {
+		endOutage(time.Now(), start)
+	}
+
+	result.Details["endpoints"] = outcomes
+	result.Details["failed_rounds"] = failed
+	result.Details["max_latency_seen"] = maxSeen.Round(time.Millisecond).String()
+	if len(outages) > 0 {
+		result.Details["outages"] = outages
+		result.Details["longest_outage"] = longest.Round(time.Millisecond).String()
+	}
+
+	if failed > maxFailures {
+		result.Message = fmt.Sprintf("%d/%d rounds failed over %s (%d outages, longest %s)",
+			failed, result.Attempts, window, len(outages), longest.Round(time.Millisecond))
+		return result, nil
+	}
+	result.Success = true
+	result.Message = fmt.Sprintf("Endpoints passed %d/%d rounds over %s", result.Attempts-failed, result.Attempts, window)
+	return result, nil
+}
+
+// resolver resolves endpoints each round and keeps the last ones resolved.
+// Job and service endpoints are looked up through the Nomad leader or Consul,
+// which may be unreachable while the fault under test lasts (a kill-leader
+// failover is leaderless for a while) although the service keeps serving,
+// so a failed lookup falls back to the last endpoints and is counted apart
+// from endpoint failures.
+type resolver struct {
+	resolve  func() ([]endpoint, error)
+	last     []endpoint
+	resolved bool
+	failures int
+	lastErr  error
+}
+
+// endpoints resolves the endpoints, or returns the last ones resolved when
+// that fails. It fails only when nothing was ever resolved.
+func (r *resolver) endpoints() ([]endpoint, error) {
+	endpoints, err := r.resolve()
+	if err == nil {
+		r.last, r.resolved = endpoints, true
+		return endpoints, nil
+	}
+	r.failures++
+	r.lastErr = err
+	if !r.resolved {
+		return nil, err
+	}
+	return r.last, nil
+}
+
+// report records resolution failures in the result details.
+func (r *resolver) report(result *Result) {
+	if r.failures == 0 {
+		return
+	}
+	result.Details["resolve_failures"] = r.failures
+	result.Details["resolve_error"] = r.lastErr.Error()
+}
+
+// resolveEndpoints returns the explicit URLs or addresses, or the endpoints
+// of a job's running allocations (job, port label) or of a Consul
+// service's passing instances (service, tag).
+func resolveEndpoints(ctx context.Context, actx *driver.AssertContext, args map[string]any, kind string, explicit []string, path, scheme string) ([]endpoint, error) {
+	var endpoints []endpoint
+	switch {
+	case len(explicit) > 0:
+		for _, raw := range explicit {
+			e := endpoint{}
+			hostport := raw
+			if kind == "http" {
+				u, err := url.Parse(raw)
+				if err != nil || u.Host == "" {
+					return nil, fmt.Errorf("invalid url %q", raw)
+				}
+				e.url, hostport = raw, u.Host
+				if u.Port() == "" {
+					port := "80"
+					if u.Scheme == "https" {
+						port = "443"
+					}
+					hostport = net.JoinHostPort(u.Hostname(), port)
+				}
+			}
+			host, port, err := net.SplitHostPort(hostport)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address %q: %w", raw, err)
+			}
+			e.host = host
+			e.port, _ = strconv.Atoi(port)
+			e.node, _ = actx.Cluster.NodeByIP(host)
+			endpoints = append(endpoints, e)
+		}
+		return endpoints, nil
+
+	case params.String(args, "job", "") != "":
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			return nil, err
+		}
+		jobID, namespace := params.String(args, "job", ""), params.String(args, "namespace", "")
+		label := params.String(args, "port", "http")
+		group := params.String(args, "group", "")
+
+		allocs, err := client.JobAllocations(ctx, jobID, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("listing allocations: %w", err)
+		}
+		for _, stub := range allocs {
+			if stub.DesiredStatus != "run" || stub.ClientStatus != nomad.AllocClientStatusRunning {
+				continue
+			}
+			if group != "" && stub.TaskGroup != group {
+				continue
+			}
+			alloc, err := client.Allocation(ctx, stub.ID)
+			if err != nil {
+				return nil, fmt.Errorf("reading allocation %s: %w", stub.ID, err)
+			}
+			port, ok := alloc.Port(label)
+			if !ok {
+				return nil, fmt.Errorf("allocation %s has no %q port", shortID(alloc.ID), label)
+			}
+			node, err := allocNode(ctx, actx, client, alloc)
+			if err != nil {
+				return nil, err
+			}
+			host := port.HostIP
+			if host == "" {
+				host = node.PrivateIP
+			}
+			endpoints = append(endpoints, endpoint{host: host, port: port.Value, node: node})
+		}
+
+	case params.String(args, "service", "") != "":
+		service := params.String(args, "service", "")
+		var entries []serviceInstance
+		var lastErr error
+		for _, server := range actx.Cluster.Servers {
+			entries, lastErr = consulInstances(ctx, actx, server, service, params.String(args, "tag", ""))
+			if lastErr == nil {
+				break
+			}
+		}
+		if lastErr != nil {
+			return nil, fmt.Errorf("reading service %s from Consul: %w", service, lastErr)
+		}
+		for _, inst := range entries {
+			node, _ := actx.Cluster.NodeByIP(inst.host)
+			endpoints = append(endpoints, endpoint{host: inst.host, port: inst.port, node: node})
+		}
+
+	default:
+		if kind == "http" {
+			return nil, fmt.Errorf("one of urls, job or service is required")
+		}
+		return nil, fmt.Errorf("one of addresses, job or service is required")
+	}
+
+	if kind == "http" {
+		for i := range endpoints {
+			e := &endpoints[i]
+			e.url = fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(e.host, strconv.Itoa(e.port)), path)
+		}
+	}
+	return endpoints, nil
+}
+
+// serviceInstance is a passing Consul service instance.
+type serviceInstance struct {
+	host string
+	port int
+}
+
+// consulInstances reads a service's passing instances through the Consul
+// agent on a node.
+func consulInstances(ctx context.Context, actx *driver.AssertContext, node driver.Node, service, tag string) ([]serviceInstance, error) {
+	client, err := actx.Driver.ConsulClient(node)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := client.HealthyServices(ctx, service, tag)
+	if err != nil {
+		return nil, err
+	}
+	instances := make([]serviceInstance, 0, len(entries))
+	for _, e := range entries {
+		instances = append(instances, serviceInstance{host: e.Address(), port: e.Service.Port})
+	}
+	return instances, nil
+}
+
+// probe checks one endpoint and returns its latency: the time to connect
+// for tcp, the time to the full response for http.
+func (p *prober) probe(ctx context.Context, actx *driver.AssertContext, e endpoint) (time.Duration, error) {
+	var latency time.Duration
+	var err error
+	if p.from == "local" {
+		latency, err = p.probeLocal(ctx, e)
+	} else {
+		latency, err = p.probeRemote(ctx, actx, e)
+	}
+	if err != nil {
+		return latency, err
+	}
+	if p.maxLatency > 0 && latency > p.maxLatency {
+		return latency, fmt.Errorf("latency %s over %s", latency.Round(time.Millisecond), p.maxLatency)
+	}
+	return latency, nil
+}
+
+// probeLocal probes from the machine running chaos. Endpoints on a
+// discovered node's private IP are reached through its public IP.
+func (p *prober) probeLocal(ctx context.Context, e endpoint) (time.Duration, error) {
+	target := e
+	if e.node != nil && e.host == e.node.PrivateIP && e.node.PublicIP != "" {
+		target.host = e.node.PublicIP
+		if e.url != "" {
+			target.url = strings.Replace(e.url, net.JoinHostPort(e.host, strconv.Itoa(e.port)), net.JoinHostPort(target.host, strconv.Itoa(e.port)), 1)
+		}
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	start := time.Now()
+
+	if p.kind == "tcp" {
+		var d net.Dialer
+		conn, err := d.DialContext(reqCtx, "tcp", target.String())
+		if err != nil {
+			return 0, err
+		}
+		conn.Close()
+		return time.Since(start), nil
+	}
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, target.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, fmt.Errorf("reading body: %w", err)
+	}
+	return time.Since(start), p.checkResponse(resp.StatusCode, body)
+}
+
+// probeRemote probes over SSH from the endpoint's own node (from=host) or
+// a named node, for ports only reachable inside the VPC.
+func (p *prober) probeRemote(ctx context.Context, actx *driver.AssertContext, e endpoint) (time.Duration, error) {
+	node := e.node
+	if p.from != "host" {
+		n, err := actx.Cluster.NodeByName(p.from)
+		if err != nil {
+			return 0, err
+		}
+		node = n
+	}
+	if node == nil {
+		return 0, fmt.Errorf("%s is not on a discovered node; use from=local or a node name", e.host)
+	}
+
+	secs := max(int(p.timeout.Seconds()), 1)
+	if p.kind == "tcp" {
+		out, err := runOnNode(ctx, actx, *node, fmt.Sprintf(
+			`timeout %d bash -c 's=$(date +%%s%%N); exec 3<>/dev/tcp/%s/%d && echo $(( $(date +%%s%%N) - s ))'`, secs, e.host, e.port))
+		if err != nil {
+			return 0, fmt.Errorf("connect from %s: %w", node.Name, err)
+		}
+		ns, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unexpected output from %s: %q", node.Name, out)
+		}
+		return time.Duration(ns), nil
+	}
+
+	// The last line carries the status and total time after the body
+	out, err := runOnNode(ctx, actx, *node, fmt.Sprintf(`curl -sS -m %d -w '\n%%{http_code} %%{time_total}' '%s'`, secs, e.url))
+	if err != nil {
+		return 0, fmt.Errorf("request from %s: %w", node.Name, err)
+	}
+	i := strings.LastIndex(out, "\n")
+	var code int
+	var total float64
+	if _, err := fmt.Sscanf(out[i+1:], "%d %f", &code, &total); err != nil {
+		return 0, fmt.Errorf("unexpected curl output from %s: %q", node.Name, out[i+1:])
+	}
+	latency := time.Duration(total * float64(time.Second))
+	return latency, p.checkResponse(code, []byte(out[:max(i, 0)]))
+}
+
+// checkResponse applies the status and body checks. Without a status list
+// any 2xx status passes.
+func (p *prober) checkResponse(code int, body []byte) error {
+	if len(p.statuses) == 0 && (code < 200 || code > 299) || len(p.statuses) > 0 && !slices.Contains(p.statuses, code) {
+		return fmt.Errorf("status %d", code)
+	}
+	if p.body != nil && !p.body.Match(body) {
+		return fmt.Errorf("body does not match %q", p.body)
+	}
+	return nil
+}
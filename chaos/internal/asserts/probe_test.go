@@ -0,0 +1,132 @@
+package asserts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/httpapi"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+)
+
+func TestResolverKeepsLastEndpoints(t *testing.T) {
+	e := endpoint{host: "10.0.1.5", port: 8080}
+	var err error
+	r := &resolver{resolve: func() ([]endpoint, error) {
+		if err != nil {
+			return nil, err
+		}
+		return []endpoint{e}, nil
+	}}
+
+	err = errors.New("no leader")
+	if _, got := r.endpoints(); got == nil {
+		t.Error("endpoints succeeded before anything was resolved")
+	}
+
+	err = nil
+	if got, _ := r.endpoints(); len(got) != 1 {
+		t.Fatalf("endpoints = %v, want one", got)
+	}
+
+	err = errors.New("no leader")
+	got, gotErr := r.endpoints()
+	if gotErr != nil || len(got) != 1 || got[0].String() != "10.0.1.5:8080" {
+		t.Errorf("endpoints during a failed lookup = %v, %v, want the last ones", got, gotErr)
+	}
+
+	result := NewResult("tcp-probe", false, "")
+	r.report(result)
+	if result.Details["resolve_failures"] != 2 || result.Details["resolve_error"] != "no leader" {
+		t.Errorf("details = %v", result.Details)
+	}
+}
+
+// leaderlessDriver has a Nomad leader only for its first leaders lookups.
+type leaderlessDriver struct {
+	driver.Driver
+	nomad   *nomad.Client
+	leaders int
+}
+
+func (d *leaderlessDriver) GetNomadLeader(_ context.Context, cluster *driver.Cluster) (*driver.Node, error) {
+	if d.leaders == 0 {
+		return nil, errors.New("no cluster leader")
+	}
+	d.leaders--
+	return &cluster.Servers[0], nil
+}
+
+func (d *leaderlessDriver) NomadClient(driver.Node) (*nomad.Client, error) {
+	return d.nomad, nil
+}
+
+func TestTCPProbeConsistentlyThroughLeaderlessRounds(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	responses := map[string]string{
+		"/v1/job/web/allocations": `[{"ID": "a1", "DesiredStatus": "run", "ClientStatus": "running"}]`,
+		"/v1/allocation/a1": fmt.Sprintf(`{"ID": "a1", "NodeID": "n1", "AllocatedResources": {"Shared": {"Ports": [
+			{"Label": "http", "Value": %d, "HostIP": "127.0.0.1"}]}}}`, port),
+		"/v1/node/n1": `{"ID": "n1", "Attributes": {"unique.network.ip-address": "127.0.0.1"}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	client, err := nomad.NewClient(httpapi.Config{Address: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cluster := &driver.Cluster{
+		Servers: []driver.Node{{Name: "server-0", Role: driver.RoleServer}},
+		Clients: []driver.Node{{Name: "client-0", Role: driver.RoleClient, PrivateIP: "127.0.0.1"}},
+	}
+	actx := driver.NewAssertContext(&leaderlessDriver{nomad: client, leaders: 1}, cluster)
+
+	result, err := (&TCPProbeAssertion{}).Check(context.Background(), actx, map[string]any{
+		"job":      "web",
+		"from":     "local",
+		"mode":     "consistently",
+		"duration": 50 * time.Millisecond,
+		"interval": 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Success {
+		t.Errorf("probe failed while the service kept serving: %s", result.Message)
+	}
+	if _, ok := result.Details["outages"]; ok {
+		t.Errorf("leaderless rounds recorded as outages: %v", result.Details["outages"])
+	}
+	if n, _ := result.Details["resolve_failures"].(int); n == 0 || n != result.Attempts-1 {
+		t.Errorf("resolve_failures = %v over %d rounds, want all but the first", result.Details["resolve_failures"], result.Attempts)
+	}
+}
@@ -0,0 +1,227 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// RaftHealthyAssertion checks the raft configuration: every server is a
+// voter and there is exactly one leader.
+type RaftHealthyAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *RaftHealthyAssertion) Name() string {
+	return "raft-healthy"
+}
+
+// Description returns a human-readable description.
+func (a *RaftHealthyAssertion) Description() string {
+	return "Verify that every server is a raft voter, the voter count is as expected and one leader exists"
+}
+
+// Check polls /v1/operator/raft/configuration until every discovered
+// server is a voter, there are at least the expected number of voters and
+// exactly one leader.
+func (a *RaftHealthyAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	timeout, err := params.Duration(args, "within", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	voters := params.Int(args, "voters", len(actx.Cluster.Servers))
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+	result.Details["expected_voters"] = voters
+
+	var problems []string
+	ok, err := eventually(ctx, result, timeout, 2*time.Second, func() bool {
+		problems = nil
+
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			problems = []string{err.Error()}
+			return false
+		}
+		raft, err := client.RaftConfiguration(ctx)
+		if err != nil {
+			problems = []string{fmt.Sprintf("reading raft configuration: %v", err)}
+			return false
+		}
+
+		servers := make(map[string]map[string]any)
+		var voterCount, leaders int
+		for _, s := range raft.Servers {
+			servers[s.Node] = map[string]any{
+				"id":      s.ID,
+				"address": s.Address,
+				"voter":   s.Voter,
+				"leader":  s.Leader,
+			}
+			if s.Voter {
+				voterCount++
+			}
+			if s.Leader {
+				leaders++
+			}
+		}
+		result.Details["servers"] = servers
+		result.Details["voters"] = voterCount
+		result.Details["index"] = raft.Index
+
+		for _, node := range actx.Cluster.Servers {
+			peer, found := raft.ServerByIP(node.PrivateIP)
+			switch {
+			case !found:
+				problems = append(problems, fmt.Sprintf("%s is not in the raft configuration", node.Name))
+			case !peer.Voter:
+				problems = append(problems, fmt.Sprintf("%s is not a voter", node.Name))
+			}
+		}
+		if voterCount < voters {
+			problems = append(problems, fmt.Sprintf("%d voters, want %d", voterCount, voters))
+		}
+		if leaders != 1 {
+			problems = append(problems, fmt.Sprintf("%d leaders in raft configuration", leaders))
+		}
+		return len(problems) == 0
+	})
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	if !ok {
+		result.Details["problems"] = problems
+		result.Message = fmt.Sprintf("Raft not healthy within %s: %s", timeout, strings.Join(problems, "; "))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("Raft healthy: %d voters, one leader", result.Details["voters"])
+	return result, nil
+}
+
+// AutopilotHealthyAssertion checks the autopilot health report: every
+// server healthy and voting, none trailing the leader, and enough failure
+// tolerance.
+type AutopilotHealthyAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *AutopilotHealthyAssertion) Name() string {
+	return "autopilot-healthy"
+}
+
+// Description returns a human-readable description.
+func (a *AutopilotHealthyAssertion) Description() string {
+	return "Verify that autopilot reports every server as a healthy voter with low log lag and enough failure tolerance"
+}
+
+// Check polls /v1/operator/autopilot/health. Lag is measured as each
+// server's LastIndex behind the highest LastIndex in the report.
+func (a *AutopilotHealthyAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	timeout, err := params.Duration(args, "within", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	maxLag := uint64(params.Int(args, "max_lag", 250))
+	minTolerance := params.Int(args, "min_failure_tolerance", (len(actx.Cluster.Servers)-1)/2)
+	servers := params.Int(args, "servers", len(actx.Cluster.Servers))
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+	result.Details["max_lag"] = maxLag
+	result.Details["min_failure_tolerance"] = minTolerance
+
+	var problems []string
+	ok, err := eventually(ctx, result, timeout, 2*time.Second, func() bool {
+		problems = nil
+
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			problems = []string{err.Error()}
+			return false
+		}
+		health, err := client.AutopilotHealth(ctx)
+		if err != nil {
+			problems = []string{fmt.Sprintf("reading autopilot health: %v", err)}
+			return false
+		}
+		result.Details["healthy"] = health.Healthy
+		result.Details["failure_tolerance"] = health.FailureTolerance
+		result.Details["servers"] = autopilotDetails(health, maxLag, &problems)
+
+		if !health.Healthy {
+			problems = append(problems, "autopilot reports the cluster unhealthy")
+		}
+		if len(health.Servers) < servers {
+			problems = append(problems, fmt.Sprintf("%d servers in report, want %d", len(health.Servers), servers))
+		}
+		if health.FailureTolerance < minTolerance {
+			problems = append(problems, fmt.Sprintf("failure tolerance %d, want at least %d", health.FailureTolerance, minTolerance))
+		}
+		return len(problems) == 0
+	})
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	if !ok {
+		result.Details["problems"] = problems
+		result.Message = fmt.Sprintf("Autopilot not healthy within %s: %s", timeout, strings.Join(problems, "; "))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("Autopilot healthy: %d servers, failure tolerance %d",
+		servers, result.Details["failure_tolerance"])
+	return result, nil
+}
+
+// autopilotDetails summarises each server in the health report, appending
+// a problem for every server that is unhealthy, not voting or lagging.
+func autopilotDetails(health *nomad.AutopilotHealth, maxLag uint64, problems *[]string) map[string]map[string]any {
+	var lastIndex uint64
+	for _, s := range health.Servers {
+		lastIndex = max(lastIndex, s.LastIndex)
+	}
+
+	sorted := append([]nomad.ServerHealth(nil), health.Servers...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	details := make(map[string]map[string]any)
+	for _, s := range sorted {
+		lag := lastIndex - s.LastIndex
+		details[s.Name] = map[string]any{
+			"id":           s.ID,
+			"address":      s.Address,
+			"serf_status":  s.SerfStatus,
+			"healthy":      s.Healthy,
+			"voter":        s.Voter,
+			"leader":       s.Leader,
+			"last_index":   s.LastIndex,
+			"last_term":    s.LastTerm,
+			"lag":          lag,
+			"last_contact": time.Duration(s.LastContact).String(),
+			"stable_since": s.StableSince,
+		}
+
+		switch {
+		case !s.Healthy:
+			*problems = append(*problems, fmt.Sprintf("%s is unhealthy (serf %s)", s.Name, s.SerfStatus))
+		case !s.Voter:
+			*problems = append(*problems, fmt.Sprintf("%s is not a voter", s.Name))
+		}
+		if lag > maxLag {
+			*problems = append(*problems, fmt.Sprintf("%s is %d entries behind", s.Name, lag))
+		}
+	}
+	return details
+}
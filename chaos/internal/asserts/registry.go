@@ -91,5 +91,27 @@ func List() []string {
 func init() {
 	// Register all built-in assertions
 	Register(&LeaderElectedAssertion{})
+	Register(&LeaderChangedAssertion{})
+	Register(&LeaderStableAssertion{})
 	Register(&NomadAPIHealthyAssertion{})
+	Register(&AllocsMigratedAssertion{})
+	Register(&TaskEventAssertion{})
+	Register(&AllocPortReachableAssertion{})
+	Register(&TaskReattachedAssertion{})
+	Register(&VirtAllocFailsAssertion{})
+	Register(&ArtifactRetryAssertion{})
+	Register(&DriverHealthyAssertion{})
+	Register(&ContainersRestoredAssertion{})
+	Register(&RaftHealthyAssertion{})
+	Register(&AutopilotHealthyAssertion{})
+	Register(&JobHealthyAssertion{})
+	Register(&DeploymentSuccessfulAssertion{})
+	Register(&AllocsRescheduledAssertion{})
+	Register(&HTTPProbeAssertion{})
+	Register(&TCPProbeAssertion{})
+	Register(&NodesReadyAssertion{})
+	Register(&CommandAssertion{})
+	Register(&APIAssertion{})
+	Register(&MetricAssertion{})
+	Register(&LogPatternAssertion{})
 }
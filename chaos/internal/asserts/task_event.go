@@ -0,0 +1,128 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+)
+
+// TaskEventAssertion checks that faulted allocations recorded the expected
+// task events, showing the driver noticed and the restart policy acted.
+type TaskEventAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *TaskEventAssertion) Name() string {
+	return "task-event"
+}
+
+// Description returns a human-readable description.
+func (a *TaskEventAssertion) Description() string {
+	return "Verify that faulted allocations record the expected task events (default: Terminated and Restarting)"
+}
+
+// Check polls the allocations and their replacements until every one has
+// recorded each of the wanted event types since the fault.
+func (a *TaskEventAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	timeout, err := params.Duration(args, "within", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	types := params.StringSlice(args, "types")
+	if len(types) == 0 {
+		types = []string{"Terminated", "Restarting"}
+	}
+	task := params.String(args, "task", "")
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+	result.Details["types"] = types
+
+	allocIDs, since, err := targetAllocs(actx, args)
+	if err != nil {
+		result.Message = err.Error()
+		return result, nil
+	}
+
+	var missing map[string][]string
+	var seen map[string][]string
+	ok, err := eventually(ctx, result, timeout, 2*time.Second, func() bool {
+		missing = make(map[string][]string)
+		seen = make(map[string][]string)
+
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			result.Details["error"] = err.Error()
+			return false
+		}
+		for _, id := range allocIDs {
+			chain, err := allocChain(ctx, client, id)
+			if err != nil && len(chain) == 0 {
+				missing[id] = []string{err.Error()}
+				continue
+			}
+			found := taskEvents(chain, task, since)
+			seen[id] = found
+			for _, want := range types {
+				if !containsFold(found, want) {
+					missing[id] = append(missing[id], want)
+				}
+			}
+		}
+		return len(missing) == 0
+	})
+	result.Details["events"] = seen
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	if !ok {
+		result.Details["missing"] = missing
+		result.Message = fmt.Sprintf("%d/%d allocations missing task events within %s", len(missing), len(allocIDs), timeout)
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("All %d allocations recorded %s", len(allocIDs), strings.Join(types, ", "))
+	return result, nil
+}
+
+// taskEvents lists "type: message" for events of the given task (or all
+// tasks) across an allocation chain that happened after since.
+func taskEvents(chain []*nomad.Allocation, task string, since time.Time) []string {
+	var out []string
+	for _, alloc := range chain {
+		for name, state := range alloc.TaskStates {
+			if task != "" && name != task {
+				continue
+			}
+			for _, ev := range state.Events {
+				if !since.IsZero() && time.Unix(0, ev.Time).Before(since) {
+					continue
+				}
+				msg := ev.DisplayMessage
+				if msg == "" {
+					msg = ev.Message
+				}
+				out = append(out, ev.Type+": "+msg)
+			}
+		}
+	}
+	return out
+}
+
+// containsFold reports whether any "type: message" entry has the given type.
+func containsFold(events []string, eventType string) bool {
+	for _, ev := range events {
+		t, _, _ := strings.Cut(ev, ":")
+		if strings.EqualFold(t, eventType) {
+			return true
+		}
+	}
+	return false
+}
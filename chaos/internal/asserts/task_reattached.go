@@ -0,0 +1,142 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+	"github.com/libvirt-standalone/chaos/internal/virt"
+)
+
+// disruptiveEvents are task event types showing a task did not survive.
+var disruptiveEvents = []string{"Terminated", "Restarting", "Killed", "Killing", "Driver Failure", "Restart Signaled"}
+
+// TaskReattachedAssertion checks that virt tasks survived a libvirtd or
+// Nomad client restart and were reattached rather than recreated.
+type TaskReattachedAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *TaskReattachedAssertion) Name() string {
+	return "task-reattached"
+}
+
+// Description returns a human-readable description.
+func (a *TaskReattachedAssertion) Description() string {
+	return "Verify that virt tasks recorded before a fault still run in the same allocation and domain, without restarts"
+}
+
+// Check compares the domains recorded by libvirtd-outage against the live
+// state until they match or the timeout expires.
+func (a *TaskReattachedAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	timeout, err := params.Duration(args, "within", 2*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+
+	var baseline []virt.AllocDomain
+	var since time.Time
+	for i := len(actx.History) - 1; i >= 0; i-- {
+		if v, ok := actx.History[i].State[driver.StateVirtDomains].([]virt.AllocDomain); ok {
+			baseline = v
+			since, _ = actx.History[i].State[driver.StateFaultTime].(time.Time)
+			break
+		}
+	}
+	if len(baseline) == 0 {
+		result.Message = "No virt domains were recorded before a fault earlier in this run"
+		return result, nil
+	}
+	result.Details["baseline"] = baseline
+
+	var problems map[string]string
+	ok, err := eventually(ctx, result, timeout, 3*time.Second, func() bool {
+		problems = make(map[string]string)
+
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			result.Details["error"] = err.Error()
+			return false
+		}
+		for _, want := range baseline {
+			if err := a.verify(ctx, actx, client, want, since); err != nil {
+				problems[want.TaskID()] = err.Error()
+			}
+		}
+		return len(problems) == 0
+	})
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+
+	if !ok {
+		result.Details["problems"] = problems
+		result.Message = fmt.Sprintf("%d/%d virt tasks were not reattached within %s", len(problems), len(baseline), timeout)
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("All %d virt tasks reattached with the same domains", len(baseline))
+	return result, nil
+}
+
+// verify checks one recorded allocation and domain.
+func (a *TaskReattachedAssertion) verify(ctx context.Context, actx *driver.AssertContext, client *nomad.Client, want virt.AllocDomain, since time.Time) error {
+	alloc, err := client.Allocation(ctx, want.AllocID)
+	if err != nil {
+		return err
+	}
+	if alloc.NextAllocation != "" {
+		return fmt.Errorf("replaced by %s", alloc.NextAllocation)
+	}
+	if alloc.ClientStatus != nomad.AllocClientStatusRunning {
+		return fmt.Errorf("allocation is %s", alloc.ClientStatus)
+	}
+	for _, ev := range taskEvents([]*nomad.Allocation{alloc}, want.Task, since) {
+		for _, t := range disruptiveEvents {
+			if containsFold([]string{ev}, t) {
+				return fmt.Errorf("task event %q", ev)
+			}
+		}
+	}
+
+	return checkDomain(ctx, actx, alloc, want)
+}
+
+// checkDomain verifies that a virt task still has exactly one domain on its
+// node, running with the recorded UUID. alloc must be the full allocation.
+func checkDomain(ctx context.Context, actx *driver.AssertContext, alloc *nomad.Allocation, want virt.AllocDomain) error {
+	node, err := actx.Cluster.NodeByName(want.Node)
+	if err != nil {
+		return err
+	}
+	ssh, err := actx.Driver.SSH(ctx, *node)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", node.Name, err)
+	}
+	defer ssh.Close()
+
+	domains, err := virt.AllocDomains(ctx, ssh, want.AllocID)
+	if err != nil {
+		return err
+	}
+	domains = virt.TaskDomains(domains, want.Task, len(virt.VirtTasks(alloc.TaskDrivers())))
+	if len(domains) != 1 {
+		var names []string
+		for _, d := range domains {
+			names = append(names, d.Name+"("+d.State+")")
+		}
+		return fmt.Errorf("expected 1 domain for task %s, found %d: %s", want.Task, len(domains), strings.Join(names, ", "))
+	}
+	if got := domains[0]; got.UUID != want.UUID || !got.Running() {
+		return fmt.Errorf("domain %s is %s with UUID %s, was %s", got.Name, got.State, got.UUID, want.UUID)
+	}
+	return nil
+}
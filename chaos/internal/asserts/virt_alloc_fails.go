@@ -0,0 +1,157 @@
+package asserts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/params"
+	"github.com/libvirt-standalone/chaos/internal/virt"
+)
+
+// failureEvents are task event types that explain why a task could not start.
+var failureEvents = []string{"Driver Failure", "Setup Failure", "Failed Validation", "Failed Artifact Download", "Not Restarting"}
+
+// VirtAllocFailsAssertion checks that virt allocations placed during a
+// storage fault fail visibly while existing domains keep running.
+type VirtAllocFailsAssertion struct{}
+
+// Name returns the assertion identifier.
+func (a *VirtAllocFailsAssertion) Name() string {
+	return "virt-alloc-fails"
+}
+
+// Description returns a human-readable description.
+func (a *VirtAllocFailsAssertion) Description() string {
+	return "Verify that new virt allocations fail with a clear task event instead of hanging, and existing domains keep running"
+}
+
+// Check waits until every allocation of the job created since the fault
+// has failed with an explanatory event, then checks the domains recorded
+// by virt-image-storage.
+func (a *VirtAllocFailsAssertion) Check(ctx context.Context, actx *driver.AssertContext, args map[string]any) (*Result, error) {
+	timeout, err := params.Duration(args, "within", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	job := params.String(args, "job", "")
+	if job == "" {
+		return nil, fmt.Errorf("job is required")
+	}
+	namespace := params.String(args, "namespace", "")
+
+	result := NewResult(a.Name(), false, "")
+	result.Details["timeout"] = timeout.String()
+	result.Details["job"] = job
+
+	var baseline []virt.AllocDomain
+	var since time.Time
+	for i := len(actx.History) - 1; i >= 0; i-- {
+		if v, ok := actx.History[i].State[driver.StateVirtDomains].([]virt.AllocDomain); ok {
+			baseline = v
+			since, _ = actx.History[i].State[driver.StateFaultTime].(time.Time)
+			break
+		}
+	}
+	if since.IsZero() {
+		result.Message = "No virt storage fault earlier in this run"
+		return result, nil
+	}
+
+	var failed, hanging map[string]string
+	ok, err := eventually(ctx, result, timeout, 5*time.Second, func() bool {
+		failed = make(map[string]string)
+		hanging = make(map[string]string)
+
+		client, err := leaderClient(ctx, actx)
+		if err != nil {
+			result.Details["error"] = err.Error()
+			return false
+		}
+		allocs, err := client.JobAllocations(ctx, job, namespace)
+		if err != nil {
+			result.Details["error"] = err.Error()
+			return false
+		}
+		for i := range allocs {
+			alloc := &allocs[i]
+			if time.Unix(0, alloc.CreateTime).Before(since) {
+				continue
+			}
+			full, err := client.Allocation(ctx, alloc.ID)
+			if err != nil {
+				hanging[alloc.ID] = err.Error()
+				continue
+			}
+			if reason := failureReason(full, since); reason != "" {
+				failed[alloc.ID] = reason
+			} else {
+				hanging[alloc.ID] = full.ClientStatus
+			}
+		}
+		return len(failed) > 0 && len(hanging) == 0
+	})
+	result.Details["failed"] = failed
+	if err != nil {
+		result.Message = "Context cancelled"
+		return result, err
+	}
+	if !ok {
+		result.Details["hanging"] = hanging
+		if len(failed)+len(hanging) == 0 {
+			result.Message = fmt.Sprintf("No new allocations of %s were placed since the fault", job)
+		} else {
+			result.Message = fmt.Sprintf("%d new allocations of %s did not fail with a clear event within %s", len(hanging), job, timeout)
+		}
+		return result, nil
+	}
+
+	// Existing domains must be unaffected, apart from ones stopped on purpose
+	client, err := leaderClient(ctx, actx)
+	if err != nil {
+		result.Message = err.Error()
+		return result, nil
+	}
+	broken := make(map[string]string)
+	for _, want := range baseline {
+		alloc, err := client.Allocation(ctx, want.AllocID)
+		if err != nil {
+			broken[want.TaskID()] = err.Error()
+			continue
+		}
+		if alloc.DesiredStatus != "run" {
+			continue
+		}
+		if err := checkDomain(ctx, actx, alloc, want); err != nil {
+			broken[want.TaskID()] = err.Error()
+		}
+	}
+	if len(broken) > 0 {
+		result.Details["broken_domains"] = broken
+		result.Message = fmt.Sprintf("New allocations failed, but %d existing domains were affected", len(broken))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("%d new allocations failed with a clear event; %d existing domains still running", len(failed), len(baseline))
+	return result, nil
+}
+
+// failureReason returns the first explanatory failure event recorded since
+// the fault, or "" if the allocation has not failed visibly yet.
+func failureReason(alloc *nomad.Allocation, since time.Time) string {
+	events := taskEvents([]*nomad.Allocation{alloc}, "", since)
+	for _, t := range failureEvents {
+		for _, ev := range events {
+			if containsFold([]string{ev}, t) {
+				return ev
+			}
+		}
+	}
+	if alloc.ClientStatus == nomad.AllocClientStatusFailed && len(events) > 0 {
+		return events[len(events)-1]
+	}
+	return ""
+}
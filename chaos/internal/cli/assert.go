@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/libvirt-standalone/chaos/internal/asserts"
 	"github.com/libvirt-standalone/chaos/internal/driver"
+	"github.com/libvirt-standalone/chaos/internal/params"
 )
 
 var (
@@ -25,19 +27,45 @@ var assertCmd = &cobra.Command{
 
 Available assertions:
   leader-elected     Check that a Nomad leader is elected
+  leader-changed     Check leadership moved off the pre-fault leader (args: previous=server-0)
+  leader-stable      Check leader and term stay stable over a window (args: duration=30s, max_changes=0)
   nomad-api-healthy  Check that a quorum of servers respond to API requests
+  allocs-migrated    Check that drained allocations are healthy elsewhere (args: allocs=id,id)
+  task-event         Check allocations recorded task events (args: allocs=id, types=Terminated,Restarting)
+  alloc-port-reachable  Check an allocation port answers (args: allocs=id, port=http, path=/)
+  task-reattached    Check VMs survived a libvirtd outage (scenarios only)
+  virt-alloc-fails   Check new virt allocs fail clearly during a storage fault (scenarios only; args: job=name)
+  artifact-retry     Check artifact download failures are retried with backoff (args: job=name, min_failures=2)
+  driver-healthy     Check task drivers' health on clients (args: driver=docker,raw_exec, healthy=true, nodes=client-0, class=, datacenter=, labels=key=value)
+  containers-restored  Check faulted docker allocations run containers again (args: allocs=id)
+  raft-healthy       Check every server is a raft voter with one leader (args: voters=3)
+  autopilot-healthy  Check autopilot health, log lag and failure tolerance (args: max_lag=250, min_failure_tolerance=1)
+  job-healthy        Check a job runs with healthy allocations per group (args: job=name, namespace=default, count=2)
+  deployment-successful  Check a job's latest deployment succeeded (args: job=name, namespace=default)
+  allocs-rescheduled  Check a job's allocations left a faulted node (args: job=name, node=client-0)
+  http-probe         Probe HTTP endpoints (args: urls=http://host/, job=name, port=http, service=name, mode=eventually|consistently, body=regex)
+  tcp-probe          Probe TCP endpoints (args: addresses=host:port, job=name, port=http, mode=eventually|consistently)
+  nodes-ready        Check clients are ready and eligible (args: nodes=client-0, class=, datacenter=dc1, labels=key=value, count=2)
+  command            Check a command's exit code and output on nodes (args: command=..., nodes=clients, stdout=regex, json_path=$.x, require=all|any|N)
+  api                Check an API response with expressions (args: api=nomad|consul|vault, path=/v1/..., url=, expect=expr, status=200)
+  metric             Check a Prometheus metric against a threshold (args: metric=name, labels=quantile=0.99, threshold=<200, rate=30s, nodes=servers)
+  log-pattern        Check how often journal lines match (args: include=panic, exclude=regex, unit=nomad, since=10m, min=0, max=0)
 
 Examples:
   chaos assert nomad-api-healthy
   chaos assert leader-elected --within 15s
-  chaos assert nomad-api-healthy --arg min_healthy=2`,
+  chaos assert nomad-api-healthy --arg min_healthy=2
+  chaos assert autopilot-healthy --within 2m --arg max_lag=50
+  chaos assert http-probe --arg job=python-server --arg mode=consistently --arg duration=2m
+  chaos assert command --arg nodes=clients --arg sudo=true --arg command='iptables -S | grep -c chaos' --arg exit_code=1
+  chaos assert api --arg api=consul --arg path=/v1/agent/members --arg "expect=len($[?(@.Status==1)]) >= 3"`,
 	Args: cobra.ExactArgs(1),
 	RunE: runAssert,
 }
 
 func init() {
 	assertCmd.Flags().StringArrayVarP(&assertArgs, "arg", "a", nil, "assertion arguments (key=value)")
-	assertCmd.Flags().DurationVarP(&assertTimeout, "timeout", "t", 30*time.Second, "assertion timeout")
+	assertCmd.Flags().DurationVarP(&assertTimeout, "timeout", "t", 30*time.Second, "assertion timeout, on top of any within or duration window")
 	assertCmd.Flags().DurationVar(&assertWithin, "within", 0, "maximum time to wait for assertion to pass (polls)")
 	rootCmd.AddCommand(assertCmd)
 }
@@ -70,7 +98,7 @@ func runAssert(cmd *cobra.Command, args []string) error {
 	}
 	defer drv.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), assertTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), assertDeadline(assertTimeout, assertionArgs))
 	defer cancel()
 
 	cluster, err := drv.Discover(ctx)
@@ -79,7 +107,7 @@ func runAssert(cmd *cobra.Command, args []string) error {
 	}
 
 	if isVerbose() {
-		fmt.Printf("Discovered %d servers\n", len(cluster.Servers))
+		fmt.Printf("Discovered %d servers, %d clients\n", len(cluster.Servers), len(cluster.Clients))
 	}
 
 	// Run the assertion
@@ -114,6 +142,19 @@ func runAssert(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// assertDeadline returns how long the assertion may run. Assertions that
+// poll for within or observe for duration need that window on top of
+// timeout, which otherwise would cancel them part way.
+func assertDeadline(timeout time.Duration, args map[string]any) time.Duration {
+	var window time.Duration
+	for _, key := range []string{"within", "duration"} {
+		if d, err := params.Duration(args, key, 0); err == nil {
+			window = max(window, d)
+		}
+	}
+	return window + timeout
+}
+
 // parseAssertArgs converts key=value strings to a map, handling int values.
 func parseAssertArgs(args []string) (map[string]any, error) {
 	result := make(map[string]any)
@@ -137,9 +178,9 @@ func parseAssertArgs(args []string) (map[string]any, error) {
 			continue
 		}
 
-		// Try to parse as int
-		var intVal int
-		if _, err := fmt.Sscanf(value, "%d", &intVal); err == nil {
+		// Try to parse as int. The whole value must be a number, so "30s"
+		// is left for the duration case below.
+		if intVal, err := strconv.Atoi(value); err == nil {
 			result[key] = intVal
 			continue
 		}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseAssertArgs(t *testing.T) {
+	got, err := parseAssertArgs([]string{
+		"healthy=false",
+		"count=3",
+		"duration=2m",
+		"within=30s",
+		"threshold=< 200",
+		"labels=quantile=0.99",
+		"version=1.9.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"healthy":   false,
+		"count":     3,
+		"duration":  2 * time.Minute,
+		"within":    30 * time.Second,
+		"threshold": "< 200",
+		"labels":    "quantile=0.99",
+		"version":   "1.9.0",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAssertArgs = %#v, want %#v", got, want)
+	}
+
+	if _, err := parseAssertArgs([]string{"novalue"}); err == nil {
+		t.Error("parseAssertArgs accepted an argument without =")
+	}
+}
+
+func TestAssertDeadline(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]any
+		want time.Duration
+	}{
+		{"no window", map[string]any{}, 30 * time.Second},
+		{"within", map[string]any{"within": 2 * time.Minute}, 150 * time.Second},
+		{"duration", map[string]any{"duration": "5m"}, 330 * time.Second},
+		{"longest window", map[string]any{"within": time.Minute, "duration": 3 * time.Minute}, 210 * time.Second},
+		{"invalid window", map[string]any{"within": "soon"}, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := assertDeadline(30*time.Second, tt.args); got != tt.want {
+				t.Errorf("assertDeadline = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
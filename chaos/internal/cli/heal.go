@@ -20,6 +20,25 @@ var healCmd = &cobra.Command{
 This command will:
   - For kill-leader: restart the Nomad service via systemd
   - For partition: remove the iptables DROP rules
+  - For clock-skew: step clocks back and restart time sync
+  - For cpu-stress/memory-pressure: stop stressors or restore unit properties
+  - For disk-fill/io-throttle/readonly-remount: delete the ballast file,
+    restore the unit's I/O limits, or remount read-write
+  - For reboot-node: start any services that did not come back
+  - For transfer-leadership: transfer leadership back to the original leader
+  - For raft-remove-peer: restart the server and wait for it to rejoin raft
+  - For server-leave: start or rejoin the server and wait for it to be a voter
+  - For wipe-raft-data: put the original raft directory back
+  - For drain-node: cancel the drain and mark the node eligible
+  - For toggle-eligibility: restore the node's original eligibility
+  - For alloc-stop/alloc-restart/alloc-kill: nothing; Nomad restarts or
+    replaces the tasks
+  - For virt-domain: resume suspended domains
+  - For libvirtd-outage: start the services and their sockets again
+  - For virt-image-storage: delete the ballast file or restore the I/O limits
+  - For artifact-outage: remove the iptables rules, netem qdisc or interposer
+  - For docker-outage: start the services again or restore the Docker socket
+  - For dns-fault: remove the iptables rules, or stop the resolver and restore resolv.conf
 
 Note: heal can only rollback the most recent inject action from this session.`,
 	Args: cobra.NoArgs,
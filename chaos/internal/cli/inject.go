@@ -24,12 +24,47 @@ var injectCmd = &cobra.Command{
 
 Available actions:
   kill-leader   Kill the Nomad leader process (args: signal=TERM|KILL)
+  capture-leader  Record the current leader for a later leader-changed assertion
   partition     Create network partition (args: source=node, target=node, bidirectional=true)
+  clock-skew    Shift node clocks with time sync stopped (args: nodes=leader, offset=30s, mode=jump|drift, duration=1m)
+  cpu-stress    Burn CPU or throttle a service (args: nodes=leader, mode=stress|throttle, workers=0, load=100, cpu_quota=20%)
+  memory-pressure  Allocate memory or cap a service (args: nodes=leader, mode=stress|throttle, size=80%, memory_max=256M)
+  disk-fill     Fill a filesystem to a percentage (args: nodes=leader, path=/opt/nomad/data, percent=95)
+  io-throttle   Throttle a unit's disk I/O via systemd IO*Max properties (args: nodes=leader, path=/opt/nomad/data, write_bps=1M, read_iops=100)
+  readonly-remount  Remount a filesystem read-only (args: nodes=leader, path=/opt/nomad/data)
+  reboot-node   Reboot and wait for return (args: nodes=leader, mode=graceful|forced, services=nomad, timeout=5m; needs --timeout above timeout)
+  transfer-leadership  Move raft leadership to a follower (args: target=random|server-N, timeout=30s)
+  raft-remove-peer  Remove a server from raft (args: node=random|leader|server-N, stop_agent=true, timeout=30s)
+  server-leave  Leave or force-leave a server (args: node=random|leader|server-N, mode=graceful|force, settle=10s)
+  snapshot      Save a raft snapshot locally (args: dir=snapshots)
+  wipe-raft-data  Wipe or corrupt raft data (args: nodes=server-N, mode=wipe|corrupt, restart=true)
+  restore-snapshot  Restore a raft snapshot (args: file=path, timeout=1m)
+  drain-node    Drain a client node (args: node=random|name|id, deadline=1m, force=false, ignore_system_jobs=false)
+  toggle-eligibility  Change client scheduling eligibility (args: node=random|name|id, eligible=false)
+  alloc-stop    Stop allocations to force a reschedule (args: job=name, group=, task=, count=1|all, alloc=id)
+  alloc-restart Restart allocation tasks in place (args: job=name, group=, task=, count=1|all, alloc=id)
+  alloc-kill    Signal tasks, or kill them on the host (args: job=name, task=, signal=KILL, hard=false)
+  virt-domain   Fault the libvirt domain of a virt task (args: job=name, mode=destroy|suspend|kill-qemu)
+  libvirtd-outage  Stop, restart or kill libvirtd on clients (args: nodes=clients, services=libvirtd,virtlogd, mode=stop|restart|kill, duration=0)
+  virt-image-storage  Fill or throttle the virt image path (args: nodes=clients, mode=fill|throttle, percent=95, write_bps=1M)
+  artifact-outage  Break an artifact source on clients (args: source=localhost:8888, mode=block|slow|corrupt, delay=2s, rate=256kbit)
+  docker-outage Stop or kill dockerd/containerd, or block the socket (args: nodes=clients, services=docker, mode=stop|restart|kill|block-socket, duration=0)
+  dns-fault     Break DNS on nodes (args: nodes=all, mode=block|servfail|nxdomain|delay, port=8600, names=active.vault.service.consul, delay=2s)
 
 Examples:
   chaos inject kill-leader
   chaos inject kill-leader --arg signal=KILL
-  chaos inject partition --arg source=server-0 --arg target=server-1`,
+  chaos inject partition --arg source=server-0 --arg target=server-1
+  chaos inject clock-skew --arg nodes=server-1 --arg offset=-45s --arg mode=drift
+  chaos inject memory-pressure --arg nodes=followers --arg size=90%
+  chaos inject disk-fill --arg nodes=server-2 --arg percent=99
+  chaos inject reboot-node --arg mode=forced --arg services=consul,nomad --timeout 10m
+  chaos inject transfer-leadership --arg target=server-2
+  chaos inject drain-node --arg node=client-1 --arg deadline=30s
+  chaos inject alloc-kill --arg job=web --arg group=frontend --arg hard=true
+  chaos inject virt-domain --arg job=python-server --arg mode=kill-qemu
+  chaos inject docker-outage --arg mode=block-socket --arg duration=1m
+  chaos inject dns-fault --arg mode=servfail --arg names=active.vault.service.consul`,
 	Args: cobra.ExactArgs(1),
 	RunE: runInject,
 }
@@ -72,8 +107,8 @@ func runInject(cmd *cobra.Command, args []string) error {
 	}
 
 	if isVerbose() {
-		fmt.Printf("Discovered %d servers\n", len(cluster.Servers))
-		for _, s := range cluster.Servers {
+		fmt.Printf("Discovered %d servers, %d clients\n", len(cluster.Servers), len(cluster.Clients))
+		for _, s := range cluster.AllNodes() {
 			fmt.Printf("  %s: %s (%s)\n", s.Name, s.PublicIP, s.PrivateIP)
 		}
 	}
@@ -90,6 +125,12 @@ func runInject(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Action completed in %v\n", time.Since(start))
 
+	if isVerbose() {
+		for k, v := range actx.Details {
+			fmt.Printf("  %s: %v\n", k, v)
+		}
+	}
+
 	// Store action context for potential rollback
 	if err := saveActionState(actx, action.Name()); err != nil {
 		fmt.Printf("Warning: could not save state for rollback: %v\n", err)
@@ -17,6 +17,7 @@ var (
 	runScenarioDir string
 	runOutputFile  string
 	runOutputJSON  bool
+	runAutoSnap    bool
 )
 
 var runCmd = &cobra.Command{
@@ -37,7 +38,11 @@ The scenario path can be:
 Examples:
   chaos run scenarios/raft/leader-failover.yaml
   chaos run raft/leader-failover
-  chaos run leader-failover --timeout 5m`,
+  chaos run leader-failover --timeout 5m
+  chaos run raft/snapshot-restore --auto-snapshot -o out/report.json
+
+With --auto-snapshot, scenarios tagged "destructive" start with a raft
+snapshot saved under snapshots/ next to the report (or the working directory).`,
 	Args: cobra.ExactArgs(1),
 	RunE: runScenario,
 }
@@ -47,6 +52,7 @@ func init() {
 	runCmd.Flags().StringVarP(&runScenarioDir, "scenarios", "s", "", "scenarios directory (default: ./scenarios)")
 	runCmd.Flags().StringVarP(&runOutputFile, "output", "o", "", "write report to file")
 	runCmd.Flags().BoolVar(&runOutputJSON, "json", false, "output report as JSON")
+	runCmd.Flags().BoolVar(&runAutoSnap, "auto-snapshot", false, "snapshot raft state before scenarios tagged destructive")
 	rootCmd.AddCommand(runCmd)
 }
 
@@ -102,8 +108,8 @@ func runScenario(cmd *cobra.Command, args []string) error {
 	}
 
 	if isVerbose() {
-		fmt.Printf("Discovered %d servers\n", len(cluster.Servers))
-		for _, s := range cluster.Servers {
+		fmt.Printf("Discovered %d servers, %d clients\n", len(cluster.Servers), len(cluster.Clients))
+		for _, s := range cluster.AllNodes() {
 			fmt.Printf("  %s: %s (%s)\n", s.Name, s.PublicIP, s.PrivateIP)
 		}
 		fmt.Println()
@@ -111,6 +117,10 @@ func runScenario(cmd *cobra.Command, args []string) error {
 
 	// Create and run the scenario
 	runner := scenario.NewRunner(drv, cluster, isVerbose())
+	runner.SetAutoSnapshot(runAutoSnap)
+	if runOutputFile != "" {
+		runner.SetArtifactDir(filepath.Dir(runOutputFile))
+	}
 	report, err := runner.Run(ctx, scen)
 
 	// Print or save report
@@ -16,6 +16,7 @@ type Config struct {
 	SSH       SSHConfig       `yaml:"ssh"`
 	Nomad     NomadConfig     `yaml:"nomad"`
 	Consul    ConsulConfig    `yaml:"consul"`
+	Vault     VaultConfig     `yaml:"vault"`
 }
 
 // ClusterConfig identifies the target cluster.
@@ -63,6 +64,13 @@ type ConsulConfig struct {
 	TLSConfig TLS    `yaml:"tls"`
 }
 
+// VaultConfig for Vault API connections.
+type VaultConfig struct {
+	Address   string `yaml:"address"`
+	Token     string `yaml:"token"`
+	TLSConfig TLS    `yaml:"tls"`
+}
+
 // TLS configuration for API connections.
 type TLS struct {
 	CACert     string `yaml:"ca_cert"`
@@ -95,6 +103,9 @@ func DefaultConfig() *Config {
 		Consul: ConsulConfig{
 			Address: "http://localhost:8500",
 		},
+		Vault: VaultConfig{
+			Address: "http://localhost:8200",
+		},
 	}
 }
 
@@ -190,4 +201,7 @@ func (c *Config) resolvePaths(baseDir string) {
 	c.Consul.TLSConfig.CACert = resolve(c.Consul.TLSConfig.CACert)
 	c.Consul.TLSConfig.ClientCert = resolve(c.Consul.TLSConfig.ClientCert)
 	c.Consul.TLSConfig.ClientKey = resolve(c.Consul.TLSConfig.ClientKey)
+	c.Vault.TLSConfig.CACert = resolve(c.Vault.TLSConfig.CACert)
+	c.Vault.TLSConfig.ClientCert = resolve(c.Vault.TLSConfig.ClientCert)
+	c.Vault.TLSConfig.ClientKey = resolve(c.Vault.TLSConfig.ClientKey)
 }
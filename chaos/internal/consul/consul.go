@@ -0,0 +1,64 @@
+// Package consul provides a minimal client for the Consul HTTP API.
+package consul
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/libvirt-standalone/chaos/internal/httpapi"
+)
+
+// Client talks to a single Consul agent.
+type Client struct {
+	*httpapi.Client
+}
+
+// NewClient creates a client from configuration. The token is sent as
+// X-Consul-Token.
+func NewClient(cfg httpapi.Config) (*Client, error) {
+	cfg.TokenHeader = "X-Consul-Token"
+	c, err := httpapi.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{Client: c}, nil
+}
+
+// ServiceEntry is one instance of a service from /v1/health/service.
+type ServiceEntry struct {
+	Node struct {
+		Node    string
+		Address string
+	}
+	Service struct {
+		ID      string
+		Service string
+		Address string
+		Port    int
+		Tags    []string
+	}
+}
+
+// Address returns the instance address, falling back to the node address
+// when the service registered none.
+func (e ServiceEntry) Address() string {
+	if e.Service.Address != "" {
+		return e.Service.Address
+	}
+	return e.Node.Address
+}
+
+// HealthyServices lists the instances of a service whose checks pass,
+// optionally filtered by tag.
+func (c *Client) HealthyServices(ctx context.Context, service, tag string) ([]ServiceEntry, error) {
+	q := url.Values{"passing": {"true"}}
+	if tag != "" {
+		q.Set("tag", tag)
+	}
+
+	var entries []ServiceEntry
+	if err := c.Get(ctx, "/v1/health/service/"+url.PathEscape(service)+"?"+q.Encode(), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -5,6 +5,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
+
+	"github.com/libvirt-standalone/chaos/internal/consul"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/vault"
 )
 
 // NodeRole identifies whether a node is a server or client.
@@ -58,6 +63,91 @@ func (c *Cluster) ServerByName(name string) (*Node, error) {
 	return nil, fmt.Errorf("server %q not found", name)
 }
 
+// NodeByName returns a server or client node by name.
+func (c *Cluster) NodeByName(name string) (*Node, error) {
+	if node, err := c.ServerByName(name); err == nil {
+		return node, nil
+	}
+	for i := range c.Clients {
+		if c.Clients[i].Name == name {
+			return &c.Clients[i], nil
+		}
+	}
+	return nil, fmt.Errorf("node %q not found", name)
+}
+
+// NodeByIP returns the node with the given private or public IP.
+func (c *Cluster) NodeByIP(ip string) (*Node, error) {
+	for _, nodes := range [][]Node{c.Servers, c.Clients} {
+		for i := range nodes {
+			if nodes[i].PrivateIP == ip || nodes[i].PublicIP == ip {
+				return &nodes[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no discovered node has IP %s", ip)
+}
+
+// SelectNodes expands node selectors into cluster nodes. A selector is a
+// node name or one of "leader", "followers", "servers", "clients" or "all".
+// Duplicates are removed while preserving order.
+func SelectNodes(ctx context.Context, drv Driver, cluster *Cluster, selectors []string) ([]Node, error) {
+	if len(selectors) == 0 {
+		return nil, fmt.Errorf("no target nodes specified")
+	}
+
+	var nodes []Node
+	seen := make(map[string]bool)
+	add := func(n Node) {
+		if !seen[n.Name] {
+			seen[n.Name] = true
+			nodes = append(nodes, n)
+		}
+	}
+
+	for _, sel := range selectors {
+		switch sel {
+		case "all":
+			for _, n := range cluster.AllNodes() {
+				add(n)
+			}
+		case "servers":
+			for _, n := range cluster.Servers {
+				add(n)
+			}
+		case "clients":
+			for _, n := range cluster.Clients {
+				add(n)
+			}
+		case "leader", "followers":
+			leader, err := drv.GetNomadLeader(ctx, cluster)
+			if err != nil {
+				return nil, fmt.Errorf("finding leader: %w", err)
+			}
+			if sel == "leader" {
+				add(*leader)
+				continue
+			}
+			for _, n := range cluster.Servers {
+				if n.Name != leader.Name {
+					add(n)
+				}
+			}
+		default:
+			node, err := cluster.NodeByName(sel)
+			if err != nil {
+				return nil, err
+			}
+			add(*node)
+		}
+	}
+
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("selectors %v matched no nodes", selectors)
+	}
+	return nodes, nil
+}
+
 // SSHClient wraps an SSH connection to a node.
 type SSHClient interface {
 	// Run executes a command and returns stdout, stderr, and exit code.
@@ -78,7 +168,8 @@ type Driver interface {
 	// Discover finds all nodes in the cluster.
 	Discover(ctx context.Context) (*Cluster, error)
 
-	// SSH opens an SSH connection to a node.
+	// SSH opens a new SSH connection to a node. Connections are not cached,
+	// so callers close them and may redial after a node reboots.
 	SSH(ctx context.Context, node Node) (SSHClient, error)
 
 	// GetNomadLeader finds the current Nomad leader.
@@ -87,6 +178,15 @@ type Driver interface {
 	// GetNomadAddr returns the Nomad API address for a node.
 	GetNomadAddr(node Node) string
 
+	// NomadClient returns an API client for the Nomad agent on a node.
+	NomadClient(node Node) (*nomad.Client, error)
+
+	// ConsulClient returns an API client for the Consul agent on a node.
+	ConsulClient(node Node) (*consul.Client, error)
+
+	// VaultClient returns an API client for the Vault server on a node.
+	VaultClient(node Node) (*vault.Client, error)
+
 	// Close releases any resources held by the driver.
 	Close() error
 }
@@ -96,6 +196,17 @@ type ActionContext struct {
 	Driver  Driver
 	Cluster *Cluster
 	State   map[string]any // For storing rollback state
+	Details map[string]any // Observations surfaced in the report
+
+	// History holds the contexts of actions executed earlier in the same
+	// scenario, oldest first, so later steps can follow their state.
+	History []*ActionContext
+
+	// ArtifactDir is where actions write files kept with the run (snapshots).
+	ArtifactDir string
+
+	// StartedAt is when the action step began executing.
+	StartedAt time.Time
 }
 
 // NewActionContext creates a new action context.
@@ -104,6 +215,7 @@ func NewActionContext(driver Driver, cluster *Cluster) *ActionContext {
 		Driver:  driver,
 		Cluster: cluster,
 		State:   make(map[string]any),
+		Details: make(map[string]any),
 	}
 }
 
@@ -111,6 +223,12 @@ func NewActionContext(driver Driver, cluster *Cluster) *ActionContext {
 type AssertContext struct {
 	Driver  Driver
 	Cluster *Cluster
+
+	// History holds the contexts of actions executed earlier in the scenario.
+	History []*ActionContext
+
+	// ScenarioStart is when the scenario began; zero outside a scenario run.
+	ScenarioStart time.Time
 }
 
 // NewAssertContext creates a new assertion context.
@@ -120,3 +238,31 @@ func NewAssertContext(driver Driver, cluster *Cluster) *AssertContext {
 		Cluster: cluster,
 	}
 }
+
+// Well-known State keys shared between actions and the assertions that
+// follow their effects.
+const (
+	// StateAllocIDs lists the allocation IDs an action disturbed.
+	StateAllocIDs = "alloc_ids"
+	// StateNomadNodeID is the Nomad client node ID an action targeted.
+	StateNomadNodeID = "nomad_node_id"
+	// StateFaultTime is when an action started disturbing its target, so
+	// assertions can ignore events that happened before it.
+	StateFaultTime = "fault_time"
+	// StateVirtDomains is the []virt.AllocDomain baseline of virt tasks
+	// running on faulted clients before the fault.
+	StateVirtDomains = "virt_domains"
+	// StateLeader is the name of the Nomad leader before the fault.
+	StateLeader = "leader"
+)
+
+// LookupState returns the most recent value of key recorded in the State of
+// any action in history.
+func LookupState(history []*ActionContext, key string) (any, bool) {
+	for i := len(history) - 1; i >= 0; i-- {
+		if v, ok := history[i].State[key]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
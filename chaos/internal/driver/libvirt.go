@@ -3,13 +3,22 @@ package driver
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
 	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/libvirt-standalone/chaos/internal/config"
+	"github.com/libvirt-standalone/chaos/internal/consul"
+	"github.com/libvirt-standalone/chaos/internal/httpapi"
+	"github.com/libvirt-standalone/chaos/internal/nomad"
+	"github.com/libvirt-standalone/chaos/internal/vault"
 )
 
 // LibvirtDriver implements Driver using Terraform outputs and SSH.
@@ -44,9 +53,13 @@ func NewLibvirtDriver(cfg *config.Config) (*LibvirtDriver, error) {
 type terraformOutput struct {
 	ServerPublicIPs  outputValue `json:"server_public_ips"`
 	ServerPrivateIPs outputValue `json:"server_private_ips"`
-	ClusterInfo      struct {
+	ClientASGName    struct {
+		Value string `json:"value"`
+	} `json:"client_asg_name"`
+	ClusterInfo struct {
 		Value struct {
 			StackName   string `json:"stack_name"`
+			Region      string `json:"region"`
 			ServerCount int    `json:"server_count"`
 		} `json:"value"`
 	} `json:"cluster_info"`
@@ -102,9 +115,80 @@ func (d *LibvirtDriver) Discover(ctx context.Context) (*Cluster, error) {
 		})
 	}
 
+	// Clients live in an autoscaling group, so their addresses are not in
+	// the terraform state. Without the AWS CLI discovery skips them with a
+	// warning, so server-only faults still work; any other failure is
+	// returned rather than leaving the client list silently empty.
+	if asg := tfOutput.ClientASGName.Value; asg != "" {
+		clients, err := d.discoverClients(ctx, asg, tfOutput.ClusterInfo.Value.Region)
+		switch {
+		case errors.Is(err, exec.ErrNotFound):
+			fmt.Fprintf(os.Stderr, "Warning: skipping client discovery: %v\n", err)
+		case err != nil:
+			return nil, fmt.Errorf("discovering clients in %s: %w", asg, err)
+		default:
+			cluster.Clients = clients
+		}
+	}
+
 	return cluster, nil
 }
 
+// discoverClients lists the running instances of the client autoscaling
+// group, named client-N in launch order.
+func (d *LibvirtDriver) discoverClients(ctx context.Context, asg, region string) ([]Node, error) {
+	args := []string{"ec2", "describe-instances",
+		"--filters", "Name=tag:aws:autoscaling:groupName,Values=" + asg, "Name=instance-state-name,Values=running",
+		"--query", "Reservations[].Instances[].[InstanceId, PublicIpAddress, PrivateIpAddress, LaunchTime]",
+		"--output", "json",
+	}
+	if region != "" {
+		args = append(args, "--region", region)
+	}
+
+	output, err := exec.CommandContext(ctx, "aws", args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("describing client instances failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("describing client instances: %w", err)
+	}
+
+	var rows [][4]*string
+	if err := json.Unmarshal(output, &rows); err != nil {
+		return nil, fmt.Errorf("parsing client instances: %w", err)
+	}
+	value := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if a, b := value(rows[i][3]), value(rows[j][3]); a != b {
+			return a < b
+		}
+		return value(rows[i][0]) < value(rows[j][0])
+	})
+
+	clients := make([]Node, 0, len(rows))
+	for i, row := range rows {
+		publicIP := value(row[1])
+		if publicIP == "" {
+			publicIP = value(row[2])
+		}
+		clients = append(clients, Node{
+			Name:      fmt.Sprintf("client-%d", i),
+			PublicIP:  publicIP,
+			PrivateIP: value(row[2]),
+			Role:      RoleClient,
+			Index:     i,
+			Labels:    map[string]string{"instance_id": value(row[0])},
+		})
+	}
+	return clients, nil
+}
+
 // SSH opens an SSH connection to a node.
 func (d *LibvirtDriver) SSH(ctx context.Context, node Node) (SSHClient, error) {
 	return NewSSHClient(ctx, node, d.sshConfig)
@@ -175,11 +259,68 @@ func (d *LibvirtDriver) queryLeader(ctx context.Context, addr string) (string, e
 
 // GetNomadAddr returns the Nomad API address for a node.
 func (d *LibvirtDriver) GetNomadAddr(node Node) string {
-	// Use configured address if set, otherwise construct from node
-	if d.config.Nomad.Address != "" && d.config.Nomad.Address != "http://localhost:4646" {
-		return d.config.Nomad.Address
+	return agentAddr(d.config.Nomad.Address, d.config.Nomad.TLSConfig, node, "4646")
+}
+
+// NomadClient returns an API client for the Nomad agent on a node, using the
+// configured ACL token and TLS settings.
+func (d *LibvirtDriver) NomadClient(node Node) (*nomad.Client, error) {
+	cfg := d.config.Nomad
+	return nomad.NewClient(httpConfig(d.GetNomadAddr(node), cfg.Token, cfg.TLSConfig))
+}
+
+// ConsulClient returns an API client for the Consul agent on a node, using
+// the configured address, ACL token and TLS settings.
+func (d *LibvirtDriver) ConsulClient(node Node) (*consul.Client, error) {
+	cfg := d.config.Consul
+	addr := agentAddr(cfg.Address, cfg.TLSConfig, node, "8500")
+	return consul.NewClient(httpConfig(addr, cfg.Token, cfg.TLSConfig))
+}
+
+// VaultClient returns an API client for the Vault server on a node, using
+// the configured address, token and TLS settings.
+func (d *LibvirtDriver) VaultClient(node Node) (*vault.Client, error) {
+	cfg := d.config.Vault
+	addr := agentAddr(cfg.Address, cfg.TLSConfig, node, "8200")
+	return vault.NewClient(httpConfig(addr, cfg.Token, cfg.TLSConfig))
+}
+
+// agentAddr returns the API address of an agent on a node. A configured
+// address on localhost, like the defaults, only supplies the scheme and
+// port and the host becomes the node's public IP; any other address is
+// used as is. Without a configured address the scheme is https when TLS
+// certificates are configured.
+func agentAddr(configured string, tlsCfg config.TLS, node Node, defaultPort string) string {
+	scheme, port := "http", defaultPort
+	if tlsCfg.CACert != "" || tlsCfg.ClientCert != "" {
+		scheme = "https"
+	}
+	if configured != "" {
+		u, err := url.Parse(configured)
+		if err != nil || u.Host == "" {
+			return configured
+		}
+		if host := u.Hostname(); host != "localhost" && host != "127.0.0.1" {
+			return configured
+		}
+		scheme = u.Scheme
+		if p := u.Port(); p != "" {
+			port = p
+		}
+	}
+	return scheme + "://" + net.JoinHostPort(node.PublicIP, port)
+}
+
+// httpConfig builds an API client configuration.
+func httpConfig(addr, token string, tlsCfg config.TLS) httpapi.Config {
+	return httpapi.Config{
+		Address:    addr,
+		Token:      token,
+		CACert:     tlsCfg.CACert,
+		ClientCert: tlsCfg.ClientCert,
+		ClientKey:  tlsCfg.ClientKey,
+		Insecure:   tlsCfg.Insecure,
 	}
-	return fmt.Sprintf("http://%s:4646", node.PublicIP)
 }
 
 // Close releases any resources held by the driver.
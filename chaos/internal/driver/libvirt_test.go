@@ -0,0 +1,33 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/libvirt-standalone/chaos/internal/config"
+)
+
+func TestAgentAddr(t *testing.T) {
+	node := Node{Name: "server-0", PublicIP: "203.0.113.10"}
+	withCA := config.TLS{CACert: "/etc/consul.d/ca.pem"}
+
+	tests := []struct {
+		name       string
+		configured string
+		tls        config.TLS
+		want       string
+	}{
+		{"default", "http://localhost:8500", config.TLS{}, "http://203.0.113.10:8500"},
+		{"unset", "", config.TLS{}, "http://203.0.113.10:8500"},
+		{"unset with TLS", "", withCA, "https://203.0.113.10:8500"},
+		{"localhost https", "https://localhost:8501", withCA, "https://203.0.113.10:8501"},
+		{"loopback without port", "https://127.0.0.1", config.TLS{}, "https://203.0.113.10:8500"},
+		{"explicit host", "https://consul.example.com:8501", config.TLS{}, "https://consul.example.com:8501"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := agentAddr(tt.configured, tt.tls, node, "8500"); got != tt.want {
+				t.Errorf("agentAddr(%q) = %q, want %q", tt.configured, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+// Package httpapi provides the HTTP transport shared by the Nomad, Consul
+// and Vault API clients: TLS setup, token headers, JSON encoding and error
+// statuses.
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config configures a Client.
+type Config struct {
+	Address    string // e.g. "http://10.0.1.10:4646"
+	Token      string // sent in TokenHeader
+	CACert     string
+	ClientCert string
+	ClientKey  string
+	Insecure   bool
+	Timeout    time.Duration
+
+	// TokenHeader carries the token, e.g. X-Nomad-Token or X-Consul-Token.
+	TokenHeader string
+}
+
+// Client talks to a single HTTP API endpoint.
+type Client struct {
+	addr        string
+	token       string
+	tokenHeader string
+	http        *http.Client
+}
+
+// NewClient creates a client from configuration.
+func NewClient(cfg Config) (*Client, error) {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.CACert != "" || cfg.ClientCert != "" || cfg.Insecure {
+		tlsConfig := &tls.Config{InsecureSkipVerify: cfg.Insecure}
+
+		if cfg.CACert != "" {
+			pem, err := os.ReadFile(cfg.CACert)
+			if err != nil {
+				return nil, fmt.Errorf("reading CA cert: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in %s", cfg.CACert)
+			}
+			tlsConfig.RootCAs = pool
+		}
+
+		if cfg.ClientCert != "" {
+			cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+			if err != nil {
+				return nil, fmt.Errorf("loading client cert: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &Client{
+		addr:        strings.TrimRight(cfg.Address, "/"),
+		token:       cfg.Token,
+		tokenHeader: cfg.TokenHeader,
+		http:        &http.Client{Timeout: timeout, Transport: transport},
+	}, nil
+}
+
+// Address returns the address this client talks to.
+func (c *Client) Address() string {
+	return c.addr
+}
+
+// APIError is returned for non-2xx responses.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// Get performs a GET request and decodes the JSON response into out.
+func (c *Client) Get(ctx context.Context, path string, out any) error {
+	return c.do(ctx, http.MethodGet, path, nil, out)
+}
+
+// Put performs a PUT request with an optional JSON body.
+func (c *Client) Put(ctx context.Context, path string, body, out any) error {
+	return c.do(ctx, http.MethodPut, path, body, out)
+}
+
+// Post performs a POST request with an optional JSON body.
+func (c *Client) Post(ctx context.Context, path string, body, out any) error {
+	return c.do(ctx, http.MethodPost, path, body, out)
+}
+
+// Delete performs a DELETE request.
+func (c *Client) Delete(ctx context.Context, path string, out any) error {
+	return c.do(ctx, http.MethodDelete, path, nil, out)
+}
+
+// do sends a request and decodes the JSON response.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	resp, err := c.Send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+		return fmt.Errorf("decoding %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// Response is a raw API response.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// MaxResponseBody caps how much of a raw response Request reads.
+const MaxResponseBody = 16 << 20
+
+// Request sends a request with an optional raw body and returns the
+// response whatever its status, for callers that check the status
+// themselves.
+func (c *Client) Request(ctx context.Context, method, path string, body io.Reader) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.addr+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" && c.tokenHeader != "" {
+		req.Header.Set(c.tokenHeader, c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxResponseBody))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s %s: %w", method, path, err)
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: data}, nil
+}
+
+// Send builds and executes a request, converting error statuses to
+// APIError. The caller closes the response body, so large payloads can be
+// streamed.
+func (c *Client) Send(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		// Raw payloads such as snapshots are sent as-is
+		reader = b
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encoding request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.addr+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" && c.tokenHeader != "" {
+		req.Header.Set(c.tokenHeader, c.token)
+	}
+	if _, raw := body.(io.Reader); body != nil && !raw {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, &APIError{
+			Method:     method,
+			Path:       path,
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(data)),
+		}
+	}
+
+	return resp, nil
+}
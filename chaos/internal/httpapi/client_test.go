@@ -0,0 +1,65 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientTokenHeaderAndErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != "secret" {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/ok":
+			w.Write([]byte(`{"Name": "consul"}`))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(Config{Address: srv.URL + "/", Token: "secret", TokenHeader: "X-Consul-Token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Address() != srv.URL {
+		t.Errorf("Address = %q, want trailing slash trimmed", client.Address())
+	}
+
+	var out struct{ Name string }
+	if err := client.Get(context.Background(), "/v1/ok", &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != "consul" {
+		t.Errorf("decoded %q, want consul", out.Name)
+	}
+
+	err = client.Get(context.Background(), "/v1/missing", nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Body != "not found" {
+		t.Errorf("Get of a missing path = %v, want a 404 APIError", err)
+	}
+
+	// Request returns error statuses instead of failing
+	resp, err := client.Request(context.Background(), http.MethodGet, "/v1/missing", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusNotFound || strings.TrimSpace(string(resp.Body)) != "not found" {
+		t.Errorf("Request = %d %q, want 404 not found", resp.StatusCode, resp.Body)
+	}
+
+	anonymous, err := NewClient(Config{Address: srv.URL, TokenHeader: "X-Consul-Token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := anonymous.Get(context.Background(), "/v1/ok", nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("Get without a token = %v, want a 403 APIError", err)
+	}
+}
@@ -0,0 +1,741 @@
+// Package jsonpath selects values from decoded JSON documents and evaluates
+// check expressions over them.
+//
+// A path starts at the root "$" (or "@", the current element inside a
+// filter) and is followed by steps: ".name" or "['name']" for an object
+// member, "[n]" for an array element (negative counts from the end), ".*"
+// or "[*]" for every member or element, and "[?(expr)]" for the elements
+// for which expr holds. Lookup also accepts a path without the leading "$",
+// so "Members[0].Name" works.
+//
+// Expressions combine paths and literals ('string', 3, true, null) with
+// comparisons (== != < <= > >=, and =~ for a regex match), arithmetic
+// (+ - * /), boolean operators (&& || !), parentheses and len(), e.g.
+//
+//	len($.Members[?(@.Status == 'alive')]) >= 3
+//
+// A path with a wildcard or filter yields the list of values it selects,
+// so len() counts them; any other path yields its single value, or null
+// when it selects nothing. Comparisons treat a one-value list as that value.
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lookup returns the values in doc selected by path, in document order.
+// Steps that do not match (a missing key, an index out of range) select
+// nothing rather than failing; only a malformed path is an error.
+func Lookup(doc any, path string) ([]any, error) {
+	p := strings.TrimSpace(path)
+	if p == "" {
+		return nil, fmt.Errorf("empty JSON path")
+	}
+	if p[0] != '$' && p[0] != '@' {
+		if p[0] != '[' {
+			p = "." + p
+		}
+		p = "$" + p
+	}
+
+	ps := &parser{src: p}
+	n, err := ps.path()
+	if err == nil && !ps.done() {
+		err = ps.errorf("unexpected %q", ps.src[ps.pos:])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON path %q: %w", path, err)
+	}
+	return n.selectFrom(doc, doc), nil
+}
+
+// Expr is a compiled check expression.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Compile parses an expression.
+func Compile(expr string) (*Expr, error) {
+	p := &parser{src: expr}
+	root, err := p.expr()
+	if err == nil && !p.done() {
+		err = p.errorf("unexpected %q", p.src[p.pos:])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", expr, err)
+	}
+	return &Expr{src: expr, root: root}, nil
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Eval evaluates the expression against doc.
+func (e *Expr) Eval(doc any) any {
+	return e.root.eval(doc, doc)
+}
+
+// Test reports whether the expression holds for doc: its value is not
+// false, null, zero, empty or missing.
+func (e *Expr) Test(doc any) bool {
+	return truthy(e.Eval(doc))
+}
+
+// Format renders a selected value the way it would be compared against a
+// scenario arg: strings unquoted, numbers without trailing zeros, null as
+// "null" and objects and arrays as compact JSON.
+func Format(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
+
+// node is an element of a parsed expression. root is the document and
+// current the element a filter is testing ("@").
+type node interface {
+	eval(root, current any) any
+}
+
+// literal is a constant.
+type literal struct{ v any }
+
+func (n literal) eval(_, _ any) any { return n.v }
+
+// pathNode selects values from the root or the current element.
+type pathNode struct {
+	relative bool
+	steps    []step
+}
+
+// definite reports whether the path selects at most one value.
+func (n *pathNode) definite() bool {
+	for _, s := range n.steps {
+		if s.kind == stepWildcard || s.kind == stepFilter {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *pathNode) selectFrom(root, current any) []any {
+	start := root
+	if n.relative {
+		start = current
+	}
+	values := []any{start}
+	for _, s := range n.steps {
+		var next []any
+		for _, v := range values {
+			next = append(next, s.selectFrom(root, v)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (n *pathNode) eval(root, current any) any {
+	values := n.selectFrom(root, current)
+	if !n.definite() {
+		if values == nil {
+			values = []any{}
+		}
+		return values
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+type stepKind int
+
+const (
+	stepKey stepKind = iota
+	stepIndex
+	stepWildcard
+	stepFilter
+)
+
+// step is one selector in a path.
+type step struct {
+	kind   stepKind
+	key    string
+	index  int
+	filter node
+}
+
+// selectFrom applies the step to one value.
+func (s step) selectFrom(root, v any) []any {
+	switch s.kind {
+	case stepKey:
+		if obj, ok := v.(map[string]any); ok {
+			if child, ok := obj[s.key]; ok {
+				return []any{child}
+			}
+		}
+	case stepIndex:
+		if arr, ok := v.([]any); ok {
+			i := s.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				return []any{arr[i]}
+			}
+		}
+	case stepWildcard, stepFilter:
+		var out []any
+		for _, child := range children(v) {
+			if s.kind == stepWildcard || truthy(s.filter.eval(root, child)) {
+				out = append(out, child)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// children returns the elements of an array or the member values of an
+// object in key order.
+func children(v any) []any {
+	switch v := v.(type) {
+	case []any:
+		return v
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make([]any, 0, len(keys))
+		for _, k := range keys {
+			out = append(out, v[k])
+		}
+		return out
+	}
+	return nil
+}
+
+// unary is a prefix operator.
+type unary struct {
+	op string
+	x  node
+}
+
+func (n unary) eval(root, current any) any {
+	v := n.x.eval(root, current)
+	if n.op == "!" {
+		return !truthy(v)
+	}
+	if f, ok := v.(float64); ok {
+		return -f
+	}
+	return nil
+}
+
+// binary is an infix operator.
+type binary struct {
+	op   string
+	x, y node
+	re   *regexp.Regexp // precompiled for =~ with a literal pattern
+}
+
+func (n binary) eval(root, current any) any {
+	x := n.x.eval(root, current)
+	switch n.op {
+	case "&&":
+		return truthy(x) && truthy(n.y.eval(root, current))
+	case "||":
+		return truthy(x) || truthy(n.y.eval(root, current))
+	}
+
+	y := n.y.eval(root, current)
+	switch n.op {
+	case "==", "!=", "=~", "<", "<=", ">", ">=":
+		x, y = single(x), single(y)
+	}
+	switch n.op {
+	case "==":
+		return equal(x, y)
+	case "!=":
+		return !equal(x, y)
+	case "=~":
+		s, ok := x.(string)
+		if !ok {
+			return false
+		}
+		re := n.re
+		if re == nil {
+			pattern, ok := y.(string)
+			if !ok {
+				return false
+			}
+			var err error
+			if re, err = regexp.Compile(pattern); err != nil {
+				return false
+			}
+		}
+		return re.MatchString(s)
+	case "<", "<=", ">", ">=":
+		c, ok := compare(x, y)
+		if !ok {
+			return false
+		}
+		switch n.op {
+		case "<":
+			return c < 0
+		case "<=":
+			return c <= 0
+		case ">":
+			return c > 0
+		default:
+			return c >= 0
+		}
+	}
+
+	a, aok := x.(float64)
+	b, bok := y.(float64)
+	if !aok || !bok {
+		if n.op == "+" {
+			if s, ok := x.(string); ok {
+				if t, ok := y.(string); ok {
+					return s + t
+				}
+			}
+		}
+		return nil
+	}
+	switch n.op {
+	case "+":
+		return a + b
+	case "-":
+		return a - b
+	case "*":
+		return a * b
+	case "/":
+		if b == 0 {
+			return nil
+		}
+		return a / b
+	}
+	return nil
+}
+
+// call is a function call.
+type call struct {
+	name string
+	args []node
+}
+
+// functions maps names to their arity.
+var functions = map[string]int{
+	"len": 1,
+}
+
+func (n call) eval(root, current any) any {
+	switch n.name {
+	case "len":
+		switch v := n.args[0].eval(root, current).(type) {
+		case []any:
+			return float64(len(v))
+		case map[string]any:
+			return float64(len(v))
+		case string:
+			return float64(len(v))
+		case nil:
+			return float64(0)
+		}
+	}
+	return nil
+}
+
+// truthy reports whether v counts as true.
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	}
+	return true
+}
+
+// single unwraps a one-element selection, so a filter matching exactly one
+// value compares like that value.
+func single(v any) any {
+	if list, ok := v.([]any); ok && len(list) == 1 {
+		return list[0]
+	}
+	return v
+}
+
+// equal compares two values structurally.
+func equal(x, y any) bool {
+	return reflect.DeepEqual(x, y)
+}
+
+// compare orders two numbers or two strings.
+func compare(x, y any) (int, bool) {
+	switch a := x.(type) {
+	case float64:
+		if b, ok := y.(float64); ok {
+			switch {
+			case a < b:
+				return -1, true
+			case a > b:
+				return 1, true
+			}
+			return 0, true
+		}
+	case string:
+		if b, ok := y.(string); ok {
+			return strings.Compare(a, b), true
+		}
+	}
+	return 0, false
+}
+
+// parser is a recursive-descent parser over the source text.
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+func (p *parser) done() bool {
+	p.skipSpace()
+	return p.pos >= len(p.src)
+}
+
+// accept consumes tok if it comes next.
+func (p *parser) accept(tok string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.src[p.pos:], tok) {
+		p.pos += len(tok)
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(tok string) error {
+	if !p.accept(tok) {
+		return p.errorf("expected %q", tok)
+	}
+	return nil
+}
+
+func (p *parser) expr() (node, error) {
+	return p.binaryLevel([]string{"||"}, func() (node, error) {
+		return p.binaryLevel([]string{"&&"}, p.not)
+	})
+}
+
+// binaryLevel parses a left-associative chain of the given operators.
+func (p *parser) binaryLevel(ops []string, operand func() (node, error)) (node, error) {
+	x, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := ""
+		for _, o := range ops {
+			if p.accept(o) {
+				op = o
+				break
+			}
+		}
+		if op == "" {
+			return x, nil
+		}
+		y, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		x = binary{op: op, x: x, y: y}
+	}
+}
+
+func (p *parser) not() (node, error) {
+	p.skipSpace()
+	if strings.HasPrefix(p.src[p.pos:], "!") && !strings.HasPrefix(p.src[p.pos:], "!=") {
+		p.pos++
+		x, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op: "!", x: x}, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (node, error) {
+	x, err := p.additive()
+	if err != nil {
+		return nil, err
+	}
+	// Longer operators first so "<=" is not read as "<"
+	for _, op := range []string{"==", "!=", "=~", "<=", ">=", "<", ">"} {
+		if !p.accept(op) {
+			continue
+		}
+		y, err := p.additive()
+		if err != nil {
+			return nil, err
+		}
+		n := binary{op: op, x: x, y: y}
+		if lit, ok := y.(literal); ok && op == "=~" {
+			pattern, ok := lit.v.(string)
+			if !ok {
+				return nil, p.errorf("=~ needs a string pattern")
+			}
+			if n.re, err = regexp.Compile(pattern); err != nil {
+				return nil, p.errorf("invalid regex: %v", err)
+			}
+		}
+		return n, nil
+	}
+	return x, nil
+}
+
+func (p *parser) additive() (node, error) {
+	return p.binaryLevel([]string{"+", "-"}, func() (node, error) {
+		return p.binaryLevel([]string{"*", "/"}, p.unary)
+	})
+}
+
+func (p *parser) unary() (node, error) {
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == '-' && (p.pos+1 >= len(p.src) || !isDigit(p.src[p.pos+1])) {
+		p.pos++
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op: "-", x: x}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of expression")
+	}
+
+	switch c := p.src[p.pos]; {
+	case c == '(':
+		p.pos++
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expect(")")
+	case c == '$' || c == '@':
+		return p.path()
+	case c == '\'' || c == '"':
+		s, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return literal{s}, nil
+	case c == '-' || isDigit(c):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && (isDigit(p.src[p.pos]) || p.src[p.pos] == '.' || p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+			p.pos++
+		}
+		f, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", p.src[start:p.pos])
+		}
+		return literal{f}, nil
+	case isNameChar(c):
+		name := p.name()
+		switch name {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		case "null":
+			return literal{nil}, nil
+		}
+		arity, ok := functions[name]
+		if !ok {
+			return nil, p.errorf("unknown identifier %q", name)
+		}
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		var args []node
+		for !p.accept(")") {
+			if len(args) > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			arg, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+		}
+		if len(args) != arity {
+			return nil, p.errorf("%s takes %d argument(s), got %d", name, arity, len(args))
+		}
+		return call{name: name, args: args}, nil
+	}
+	return nil, p.errorf("unexpected %q", p.src[p.pos])
+}
+
+// path parses "$" or "@" followed by steps.
+func (p *parser) path() (*pathNode, error) {
+	p.skipSpace()
+	n := &pathNode{relative: p.src[p.pos] == '@'}
+	p.pos++
+
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '.':
+			p.pos++
+			if p.pos < len(p.src) && p.src[p.pos] == '*' {
+				p.pos++
+				n.steps = append(n.steps, step{kind: stepWildcard})
+				continue
+			}
+			name := p.name()
+			if name == "" {
+				return nil, p.errorf("expected member name after '.'")
+			}
+			n.steps = append(n.steps, step{kind: stepKey, key: name})
+		case '[':
+			p.pos++
+			s, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+			n.steps = append(n.steps, s)
+		default:
+			return n, nil
+		}
+	}
+	return n, nil
+}
+
+// bracket parses the contents of a [...] step after the "[".
+func (p *parser) bracket() (step, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return step{}, p.errorf("unterminated [")
+	}
+
+	var s step
+	switch c := p.src[p.pos]; {
+	case c == '*':
+		p.pos++
+		s = step{kind: stepWildcard}
+	case c == '?':
+		p.pos++
+		filter, err := p.expr()
+		if err != nil {
+			return step{}, err
+		}
+		s = step{kind: stepFilter, filter: filter}
+	case c == '\'' || c == '"':
+		key, err := p.quoted()
+		if err != nil {
+			return step{}, err
+		}
+		s = step{kind: stepKey, key: key}
+	default:
+		start := p.pos
+		if c == '-' {
+			p.pos++
+		}
+		for p.pos < len(p.src) && isDigit(p.src[p.pos]) {
+			p.pos++
+		}
+		i, err := strconv.Atoi(p.src[start:p.pos])
+		if err != nil {
+			return step{}, p.errorf("unsupported selector")
+		}
+		s = step{kind: stepIndex, index: i}
+	}
+	return s, p.expect("]")
+}
+
+// quoted parses a single- or double-quoted string with backslash escapes.
+func (p *parser) quoted() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		switch {
+		case c == '\\' && p.pos < len(p.src):
+			b.WriteByte(p.src[p.pos])
+			p.pos++
+		case c == quote:
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// name parses a member or function name.
+func (p *parser) name() string {
+	start := p.pos
+	for p.pos < len(p.src) && isNameChar(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,159 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const testDoc = `{
+	"Leader": "10.0.0.1:4647",
+	"Members": [
+		{"Name": "server-0", "Status": "alive", "Port": 4648, "Tags": {"role": "nomad"}},
+		{"Name": "server-1", "Status": "alive", "Port": 4648, "Tags": {"role": "nomad"}},
+		{"Name": "server-2", "Status": "failed", "Port": 4648, "Tags": {"role": "nomad"}}
+	],
+	"odd key": {"a,b": "comma", "it's": "quote"},
+	"Enabled": true,
+	"Missing": null
+}`
+
+func decode(t *testing.T) any {
+	t.Helper()
+	var doc any
+	if err := json.Unmarshal([]byte(testDoc), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestLookup(t *testing.T) {
+	doc := decode(t)
+	tests := []struct {
+		path string
+		want []any
+	}{
+		{"$.Leader", []any{"10.0.0.1:4647"}},
+		{"Leader", []any{"10.0.0.1:4647"}},
+		{"Members[0].Name", []any{"server-0"}},
+		{"$.Members[-1].Name", []any{"server-2"}},
+		{"$.Members[*].Status", []any{"alive", "alive", "failed"}},
+		{"$.Members.*.Port", []any{4648.0, 4648.0, 4648.0}},
+		{"$.Members[?(@.Status == 'failed')].Name", []any{"server-2"}},
+		{"$.Members[?(@.Status != 'failed' && @.Name =~ '-1$')].Name", []any{"server-1"}},
+		{"$['odd key']['a,b']", []any{"comma"}},
+		{`$["odd key"]['it\'s']`, []any{"quote"}},
+		{"$.Members[5].Name", nil},
+		{"$.Nope.Name", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := Lookup(doc, tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lookup = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupErrors(t *testing.T) {
+	doc := decode(t)
+	for _, path := range []string{
+		"",
+		"$.",
+		"$.Members[",
+		"$.Members[abc]",
+		"$['unterminated]",
+		"$.Members[0",
+		"$.Leader extra",
+	} {
+		if _, err := Lookup(doc, path); err == nil {
+			t.Errorf("Lookup(%q) succeeded", path)
+		}
+	}
+}
+
+func TestExprTest(t *testing.T) {
+	doc := decode(t)
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"len($.Members[?(@.Status == 'alive')]) >= 2", true},
+		{"len($.Members[?(@.Status == 'alive')]) >= 3", false},
+		{"len($.Members) == 3", true},
+		{"$.Leader == '10.0.0.1:4647'", true},
+		{`$.Leader == "10.0.0.2:4647"`, false},
+		{"$.Leader =~ '^10\\.0\\.0\\.'", true},
+		{"$.Members[0].Port > 4000 && $.Members[0].Port <= 4648", true},
+		{"$.Members[0].Port < 4648", false},
+		{"$.Members[0].Port * 2 - 1 == 9295", true},
+		{"-$.Members[0].Port == -4648", true},
+		{"$.Members[0].Port / 2 != 2324", false},
+		{"$.Enabled", true},
+		{"!$.Enabled", false},
+		{"$.Missing == null", true},
+		{"$.Missing", false},
+		{"$.Nope", false},
+		{"$.Enabled || $.Missing", true},
+		{"($.Missing || false) == false", true},
+		{"$['odd key']['a,b'] == 'comma'", true},
+		{"$.Members[?(@.Tags.role == 'nomad')].Name == 'server-0'", false},
+		{"$.Members[?(@.Name == 'server-0')].Name == 'server-0'", true},
+		{"'10' < 9", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := Compile(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := e.Test(doc); got != tt.want {
+				t.Errorf("Test = %v, want %v (value %#v)", got, tt.want, e.Eval(doc))
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"$.Leader ==",
+		"($.Leader == 'x'",
+		"len($.Members, 1)",
+		"size($.Members)",
+		"bogus",
+		"'unterminated",
+		"1.2.3 > 0",
+		"$.Leader == 'x' )",
+		"$.Leader =~ 3",
+		"$.Leader =~ '('",
+	} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Compile(%q) succeeded", expr)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		v    any
+		want string
+	}{
+		{nil, "null"},
+		{"alive", "alive"},
+		{4648.0, "4648"},
+		{0.5, "0.5"},
+		{true, "true"},
+		{[]any{"a", 1.0}, `["a",1]`},
+		{map[string]any{"k": "v"}, `{"k":"v"}`},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.v); got != tt.want {
+			t.Errorf("Format(%#v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
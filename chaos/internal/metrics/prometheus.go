@@ -0,0 +1,221 @@
+// Package metrics parses the Prometheus text exposition format served by
+// Nomad's /v1/metrics?format=prometheus.
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Sample is one series value from a scrape.
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// Key identifies the sample's series: its name and sorted labels.
+func (s Sample) Key() string {
+	if len(s.Labels) == 0 {
+		return s.Name
+	}
+	names := make([]string, 0, len(s.Labels))
+	for k := range s.Labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, k := range names {
+		pairs[i] = fmt.Sprintf("%s=%q", k, s.Labels[k])
+	}
+	return s.Name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Parse reads samples from the text format. Comments, HELP and TYPE lines
+// and timestamps are ignored.
+func Parse(data []byte) ([]Sample, error) {
+	var samples []Sample
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		s, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		samples = append(samples, s)
+	}
+	return samples, scanner.Err()
+}
+
+// parseLine parses `name{label="value",...} value [timestamp]`.
+func parseLine(line string) (Sample, error) {
+	s := Sample{Labels: make(map[string]string)}
+
+	end := strings.IndexAny(line, "{ \t")
+	if end <= 0 {
+		return s, fmt.Errorf("malformed sample %q", line)
+	}
+	s.Name, line = line[:end], line[end:]
+
+	if line[0] == '{' {
+		rest, err := parseLabels(line[1:], s.Labels)
+		if err != nil {
+			return s, err
+		}
+		line = rest
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return s, fmt.Errorf("sample %s has no value", s.Name)
+	}
+	v, err := parseValue(fields[0])
+	if err != nil {
+		return s, fmt.Errorf("sample %s: %w", s.Name, err)
+	}
+	s.Value = v
+	return s, nil
+}
+
+// parseLabels reads label pairs up to the closing brace and returns the
+// remainder of the line.
+func parseLabels(line string, labels map[string]string) (string, error) {
+	for {
+		line = strings.TrimLeft(line, " \t,")
+		if line == "" {
+			return "", fmt.Errorf("unterminated label set")
+		}
+		if line[0] == '}' {
+			return line[1:], nil
+		}
+
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 || eq+1 >= len(line) || line[eq+1] != '"' {
+			return "", fmt.Errorf("malformed label in %q", line)
+		}
+		name := strings.TrimSpace(line[:eq])
+		line = line[eq+2:]
+
+		var b strings.Builder
+		closed := false
+		for i := 0; i < len(line); i++ {
+			c := line[i]
+			if c == '\\' && i+1 < len(line) {
+				i++
+				if line[i] == 'n' {
+					b.WriteByte('\n')
+				} else {
+					b.WriteByte(line[i])
+				}
+				continue
+			}
+			if c == '"' {
+				line, closed = line[i+1:], true
+				break
+			}
+			b.WriteByte(c)
+		}
+		if !closed {
+			return "", fmt.Errorf("unterminated value for label %s", name)
+		}
+		labels[name] = b.String()
+	}
+}
+
+// parseValue parses a sample value, including NaN and ±Inf.
+func parseValue(s string) (float64, error) {
+	switch s {
+	case "NaN":
+		return math.NaN(), nil
+	case "+Inf", "Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+// Matcher selects series by label: equal (=), not equal (!=), regex match
+// (=~) or regex non-match (!~). Regexes are anchored.
+type Matcher struct {
+	Label string
+	Op    string
+	Value string
+	re    *regexp.Regexp
+}
+
+// ParseMatcher parses a matcher such as `quantile=0.99`, `host!=server-0`
+// or `method=~Raft.*`.
+func ParseMatcher(s string) (Matcher, error) {
+	for _, op := range []string{"=~", "!~", "!=", "="} {
+		i := strings.Index(s, op)
+		if i <= 0 {
+			continue
+		}
+		m := Matcher{
+			Label: strings.TrimSpace(s[:i]),
+			Op:    op,
+			Value: strings.Trim(strings.TrimSpace(s[i+len(op):]), `"`),
+		}
+		if op == "=~" || op == "!~" {
+			re, err := regexp.Compile("^(?:" + m.Value + ")$")
+			if err != nil {
+				return m, fmt.Errorf("invalid label regex %q: %w", m.Value, err)
+			}
+			m.re = re
+		}
+		return m, nil
+	}
+	return Matcher{}, fmt.Errorf("invalid label matcher %q: want label=value, !=, =~ or !~", s)
+}
+
+// Matches reports whether the labels satisfy the matcher. A missing label
+// is treated as empty.
+func (m Matcher) Matches(labels map[string]string) bool {
+	v := labels[m.Label]
+	switch m.Op {
+	case "=":
+		return v == m.Value
+	case "!=":
+		return v != m.Value
+	case "=~":
+		return m.re.MatchString(v)
+	default:
+		return !m.re.MatchString(v)
+	}
+}
+
+// Select returns the samples named name that satisfy every matcher.
+func Select(samples []Sample, name string, matchers []Matcher) []Sample {
+	var out []Sample
+	for _, s := range samples {
+		if s.Name != name {
+			continue
+		}
+		ok := true
+		for _, m := range matchers {
+			if !m.Matches(s.Labels) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+)
+
+const scrape = `# HELP nomad_raft_commitTime nomad_raft_commitTime
+# TYPE nomad_raft_commitTime summary
+nomad_raft_commitTime{host="server-0",quantile="0.5"} 1.5
+nomad_raft_commitTime{host="server-0",quantile="0.99"} 12.25 1700000000000
+nomad_raft_commitTime_sum{host="server-0"} 420
+nomad_nomad_rpc_request{host="server-0",note="say \"hi\"\n"} 7
+nomad_runtime_alloc_bytes NaN
+nomad_client_up +Inf
+
+go_goroutines 42
+`
+
+func TestParse(t *testing.T) {
+	samples, err := Parse([]byte(scrape))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 7 {
+		t.Fatalf("Parse returned %d samples, want 7", len(samples))
+	}
+
+	s := samples[1]
+	if s.Name != "nomad_raft_commitTime" || s.Labels["quantile"] != "0.99" || s.Value != 12.25 {
+		t.Errorf("sample 1 = %+v", s)
+	}
+	if got := samples[3].Labels["note"]; got != "say \"hi\"\n" {
+		t.Errorf("escaped label = %q", got)
+	}
+	if !math.IsNaN(samples[4].Value) {
+		t.Errorf("NaN sample = %v", samples[4].Value)
+	}
+	if !math.IsInf(samples[5].Value, 1) {
+		t.Errorf("+Inf sample = %v", samples[5].Value)
+	}
+	if got, want := samples[1].Key(), `nomad_raft_commitTime{host="server-0",quantile="0.99"}`; got != want {
+		t.Errorf("Key = %s, want %s", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{
+		"{quantile=\"0.5\"} 1",
+		"nomad_up",
+		"nomad_up{host=\"a\" 1",
+		"nomad_up{host=a} 1",
+		"nomad_up{host=\"a} 1",
+		"nomad_up one",
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Parse(%q) succeeded", data)
+		}
+	}
+}
+
+func TestParseMatcher(t *testing.T) {
+	tests := []struct {
+		in    string
+		op    string
+		label string
+		value string
+	}{
+		{"quantile=0.99", "=", "quantile", "0.99"},
+		{`host = "server-0"`, "=", "host", "server-0"},
+		{"host!=server-0", "!=", "host", "server-0"},
+		{"method=~Raft.*", "=~", "method", "Raft.*"},
+		{"method!~Raft.{1,3}", "!~", "method", "Raft.{1,3}"},
+	}
+	for _, tt := range tests {
+		m, err := ParseMatcher(tt.in)
+		if err != nil {
+			t.Errorf("ParseMatcher(%q): %v", tt.in, err)
+			continue
+		}
+		if m.Op != tt.op || m.Label != tt.label || m.Value != tt.value {
+			t.Errorf("ParseMatcher(%q) = %s %s %s, want %s %s %s", tt.in, m.Label, m.Op, m.Value, tt.label, tt.op, tt.value)
+		}
+	}
+
+	for _, in := range []string{"quantile", "=0.99", "method=~(", ""} {
+		if _, err := ParseMatcher(in); err == nil {
+			t.Errorf("ParseMatcher(%q) succeeded", in)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	samples := []Sample{
+		{Name: "rpc", Labels: map[string]string{"method": "Raft.Apply"}, Value: 1},
+		{Name: "rpc", Labels: map[string]string{"method": "Raft.AppendEntries"}, Value: 2},
+		{Name: "rpc", Labels: map[string]string{"method": "Node.Register"}, Value: 3},
+		{Name: "rpc", Labels: map[string]string{}, Value: 4},
+		{Name: "other", Labels: map[string]string{"method": "Raft.Apply"}, Value: 5},
+	}
+	tests := []struct {
+		name     string
+		matchers []string
+		want     []float64
+	}{
+		{"no matchers", nil, []float64{1, 2, 3, 4}},
+		{"equal", []string{"method=Raft.Apply"}, []float64{1}},
+		{"anchored regex", []string{"method=~Raft"}, nil},
+		{"regex", []string{"method=~Raft.*"}, []float64{1, 2}},
+		{"not equal keeps missing label", []string{"method!=Node.Register"}, []float64{1, 2, 4}},
+		{"every matcher", []string{"method=~Raft.*", "method!~.*Apply"}, []float64{2}},
+		{"missing label is empty", []string{"method="}, []float64{4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var matchers []Matcher
+			for _, s := range tt.matchers {
+				m, err := ParseMatcher(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				matchers = append(matchers, m)
+			}
+			var got []float64
+			for _, s := range Select(samples, "rpc", matchers) {
+				got = append(got, s.Value)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Select = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Select = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/libvirt-standalone/chaos/internal/httpapi"
+)
+
+// JoinResponse is the response of /v1/agent/join.
+type JoinResponse struct {
+	NumJoined int    `json:"num_joined"`
+	Error     string `json:"error"`
+}
+
+// Join asks the agent to join the gossip pool via the given serf addresses.
+func (c *Client) Join(ctx context.Context, addrs ...string) (int, error) {
+	q := url.Values{}
+	for _, a := range addrs {
+		q.Add("address", a)
+	}
+
+	var resp JoinResponse
+	if err := c.Put(ctx, "/v1/agent/join?"+q.Encode(), nil, &resp); err != nil {
+		return 0, err
+	}
+	if resp.Error != "" {
+		return resp.NumJoined, fmt.Errorf("joining %v: %s", addrs, resp.Error)
+	}
+	return resp.NumJoined, nil
+}
+
+// AgentMember is a serf gossip pool member from /v1/agent/members.
+type AgentMember struct {
+	Name   string
+	Addr   string
+	Port   uint16
+	Status string
+	Tags   map[string]string
+}
+
+// membersResponse wraps the /v1/agent/members reply.
+type membersResponse struct {
+	ServerName   string
+	ServerRegion string
+	ServerDC     string
+	Members      []AgentMember
+}
+
+// Members returns the server gossip pool as seen by this agent.
+func (c *Client) Members(ctx context.Context) ([]AgentMember, error) {
+	var resp membersResponse
+	if err := c.Get(ctx, "/v1/agent/members", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Members, nil
+}
+
+// ForceLeave forces a gossip member into the "left" state.
+func (c *Client) ForceLeave(ctx context.Context, node string) error {
+	if err := c.Put(ctx, "/v1/agent/force-leave?node="+url.QueryEscape(node), nil, nil); err != nil {
+		return fmt.Errorf("force-leaving %s: %w", node, err)
+	}
+	return nil
+}
+
+// RaftStats is the raft section of /v1/agent/self, as seen by one server.
+type RaftStats struct {
+	State        string // Leader, Follower or Candidate
+	Term         uint64
+	LastLogIndex uint64
+	AppliedIndex uint64
+}
+
+// RaftStats reads the agent's raft state and term. It fails on clients,
+// which report no raft stats.
+func (c *Client) RaftStats(ctx context.Context) (*RaftStats, error) {
+	var self struct {
+		Stats map[string]map[string]string `json:"stats"`
+	}
+	if err := c.Get(ctx, "/v1/agent/self", &self); err != nil {
+		return nil, err
+	}
+	raft, ok := self.Stats["raft"]
+	if !ok {
+		return nil, fmt.Errorf("agent reports no raft stats")
+	}
+
+	stats := &RaftStats{State: raft["state"]}
+	for key, dst := range map[string]*uint64{
+		"term":           &stats.Term,
+		"last_log_index": &stats.LastLogIndex,
+		"applied_index":  &stats.AppliedIndex,
+	} {
+		if v, ok := raft[key]; ok {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid raft %s %q", key, v)
+			}
+			*dst = n
+		}
+	}
+	return stats, nil
+}
+
+// PrometheusMetrics returns the agent's metrics in the Prometheus text
+// format. The agent must set telemetry.prometheus_metrics.
+func (c *Client) PrometheusMetrics(ctx context.Context) ([]byte, error) {
+	resp, err := c.Send(ctx, http.MethodGet, "/v1/metrics?format=prometheus", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, httpapi.MaxResponseBody))
+	if err != nil {
+		return nil, fmt.Errorf("reading metrics: %w", err)
+	}
+	return data, nil
+}
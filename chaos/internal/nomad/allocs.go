@@ -0,0 +1,191 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Allocation client statuses.
+const (
+	AllocClientStatusPending  = "pending"
+	AllocClientStatusRunning  = "running"
+	AllocClientStatusComplete = "complete"
+	AllocClientStatusFailed   = "failed"
+	AllocClientStatusLost     = "lost"
+)
+
+// DockerAllocIDLabel is the label the docker driver sets on every task
+// container to the ID of its allocation.
+const DockerAllocIDLabel = "com.hashicorp.nomad.alloc_id"
+
+// Allocation is the subset of allocation fields chaos inspects. It decodes
+// both list stubs and full allocations.
+type Allocation struct {
+	ID                 string
+	Name               string
+	Namespace          string
+	NodeID             string
+	NodeName           string
+	JobID              string
+	JobType            string // list stubs only
+	Job                *Job   // full allocations only
+	TaskGroup          string
+	DesiredStatus      string
+	DesiredTransition  DesiredTransition
+	ClientStatus       string
+	PreviousAllocation string
+	NextAllocation     string
+	DeploymentStatus   *AllocDeploymentStatus
+	TaskStates         map[string]*TaskState
+	AllocatedResources *AllocatedResources // full allocations only
+	CreateTime         int64
+	ModifyTime         int64
+}
+
+// Type returns the allocation's job type from either a list stub or a full
+// allocation.
+func (a *Allocation) Type() string {
+	if a.JobType != "" {
+		return a.JobType
+	}
+	if a.Job != nil {
+		return a.Job.Type
+	}
+	return ""
+}
+
+// TaskDrivers maps the tasks of the allocation's group to their drivers.
+// It needs a full allocation.
+func (a *Allocation) TaskDrivers() map[string]string {
+	drivers := make(map[string]string)
+	if a.Job == nil {
+		return drivers
+	}
+	for _, tg := range a.Job.TaskGroups {
+		if tg.Name != a.TaskGroup {
+			continue
+		}
+		for _, t := range tg.Tasks {
+			drivers[t.Name] = t.Driver
+		}
+	}
+	return drivers
+}
+
+// DesiredTransition is the scheduler's pending intent for an allocation.
+// Migrate is set when a drain moves the allocation off its node.
+type DesiredTransition struct {
+	Migrate    *bool
+	Reschedule *bool
+}
+
+// Migrating reports whether a drain marked the allocation for migration.
+func (a *Allocation) Migrating() bool {
+	return a.DesiredTransition.Migrate != nil && *a.DesiredTransition.Migrate
+}
+
+// AllocatedResources holds the resources assigned to an allocation.
+type AllocatedResources struct {
+	Shared struct {
+		Ports []AllocatedPort
+	}
+}
+
+// AllocatedPort is a port mapped for the allocation's group network.
+type AllocatedPort struct {
+	Label  string
+	Value  int // host port
+	To     int // port inside the task
+	HostIP string
+}
+
+// Port returns the allocated port with the given label.
+func (a *Allocation) Port(label string) (AllocatedPort, bool) {
+	if a.AllocatedResources == nil {
+		return AllocatedPort{}, false
+	}
+	for _, p := range a.AllocatedResources.Shared.Ports {
+		if p.Label == label {
+			return p, true
+		}
+	}
+	return AllocatedPort{}, false
+}
+
+// AllocDeploymentStatus reports deployment health for an allocation.
+type AllocDeploymentStatus struct {
+	Healthy *bool
+}
+
+// IsHealthy reports whether the deployment marked the allocation healthy.
+// Allocations outside a deployment fall back to being running.
+func (a *Allocation) IsHealthy() bool {
+	if a.DeploymentStatus != nil && a.DeploymentStatus.Healthy != nil {
+		return *a.DeploymentStatus.Healthy
+	}
+	return a.ClientStatus == AllocClientStatusRunning
+}
+
+// TaskState is the runtime state of a task within an allocation.
+type TaskState struct {
+	State    string
+	Failed   bool
+	Restarts uint64
+	Events   []*TaskEvent
+}
+
+// TaskEvent is a single entry in a task's event history.
+type TaskEvent struct {
+	Type           string
+	Time           int64 // unix nanoseconds
+	DisplayMessage string
+	Message        string
+	DriverError    string
+	KillReason     string
+	RestartReason  string
+}
+
+// Allocation reads a single allocation by ID.
+func (c *Client) Allocation(ctx context.Context, id string) (*Allocation, error) {
+	var alloc Allocation
+	if err := c.Get(ctx, "/v1/allocation/"+url.PathEscape(id), &alloc); err != nil {
+		return nil, err
+	}
+	return &alloc, nil
+}
+
+// JobAllocations lists the allocations of a job in a namespace.
+func (c *Client) JobAllocations(ctx context.Context, jobID, namespace string) ([]Allocation, error) {
+	var allocs []Allocation
+	if err := c.Get(ctx, jobPath(jobID, "/allocations", namespace), &allocs); err != nil {
+		return nil, err
+	}
+	return allocs, nil
+}
+
+// StopAllocation stops an allocation, causing the scheduler to replace it.
+func (c *Client) StopAllocation(ctx context.Context, id string) error {
+	if err := c.Post(ctx, "/v1/allocation/"+url.PathEscape(id)+"/stop", nil, nil); err != nil {
+		return fmt.Errorf("stopping allocation %s: %w", id, err)
+	}
+	return nil
+}
+
+// RestartAllocation restarts a task in place, or every task when task is empty.
+func (c *Client) RestartAllocation(ctx context.Context, id, task string) error {
+	body := map[string]any{"TaskName": task, "AllTasks": task == ""}
+	if err := c.Put(ctx, "/v1/client/allocation/"+url.PathEscape(id)+"/restart", body, nil); err != nil {
+		return fmt.Errorf("restarting allocation %s: %w", id, err)
+	}
+	return nil
+}
+
+// SignalAllocation sends a signal to a task, or every task when task is empty.
+func (c *Client) SignalAllocation(ctx context.Context, id, task, signal string) error {
+	body := map[string]string{"Task": task, "Signal": signal}
+	if err := c.Put(ctx, "/v1/client/allocation/"+url.PathEscape(id)+"/signal", body, nil); err != nil {
+		return fmt.Errorf("signalling allocation %s: %w", id, err)
+	}
+	return nil
+}
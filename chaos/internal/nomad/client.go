@@ -0,0 +1,36 @@
+// Package nomad provides a minimal client for the Nomad HTTP API.
+//
+// It covers only the endpoints the chaos actions and assertions need, and
+// avoids pulling in the full github.com/hashicorp/nomad/api module.
+package nomad
+
+import (
+	"context"
+
+	"github.com/libvirt-standalone/chaos/internal/httpapi"
+)
+
+// Client talks to a single Nomad agent.
+type Client struct {
+	*httpapi.Client
+}
+
+// NewClient creates a client from configuration. The token is sent as
+// X-Nomad-Token.
+func NewClient(cfg httpapi.Config) (*Client, error) {
+	cfg.TokenHeader = "X-Nomad-Token"
+	c, err := httpapi.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{Client: c}, nil
+}
+
+// Leader returns the raft address of the current leader ("" if none).
+func (c *Client) Leader(ctx context.Context) (string, error) {
+	var leader string
+	if err := c.Get(ctx, "/v1/status/leader", &leader); err != nil {
+		return "", err
+	}
+	return leader, nil
+}
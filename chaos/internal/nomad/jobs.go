@@ -0,0 +1,94 @@
+package nomad
+
+import (
+	"context"
+	"net/url"
+)
+
+// Job statuses.
+const (
+	JobStatusPending = "pending"
+	JobStatusRunning = "running"
+	JobStatusDead    = "dead"
+)
+
+// Deployment statuses.
+const (
+	DeploymentStatusRunning    = "running"
+	DeploymentStatusSuccessful = "successful"
+	DeploymentStatusFailed     = "failed"
+	DeploymentStatusCancelled  = "cancelled"
+)
+
+// Job is the subset of job fields chaos inspects.
+type Job struct {
+	ID         string
+	Name       string
+	Namespace  string
+	Type       string
+	Status     string
+	Stop       bool
+	Version    uint64
+	TaskGroups []TaskGroup
+}
+
+// TaskGroup is a job's task group and its desired count.
+type TaskGroup struct {
+	Name  string
+	Count int
+	Tasks []Task
+}
+
+// Task is a task within a task group.
+type Task struct {
+	Name   string
+	Driver string
+}
+
+// Job reads a job by ID.
+func (c *Client) Job(ctx context.Context, id, namespace string) (*Job, error) {
+	var job Job
+	if err := c.Get(ctx, jobPath(id, "", namespace), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Deployment is a job deployment with per-group progress.
+type Deployment struct {
+	ID                string
+	JobID             string
+	Namespace         string
+	JobVersion        uint64
+	Status            string
+	StatusDescription string
+	TaskGroups        map[string]*DeploymentState
+	CreateIndex       uint64
+}
+
+// DeploymentState is one task group's progress within a deployment.
+type DeploymentState struct {
+	DesiredTotal    int
+	PlacedAllocs    int
+	HealthyAllocs   int
+	UnhealthyAllocs int
+}
+
+// LatestDeployment reads a job's most recent deployment, or nil if the job
+// has never been deployed.
+func (c *Client) LatestDeployment(ctx context.Context, jobID, namespace string) (*Deployment, error) {
+	var d *Deployment
+	if err := c.Get(ctx, jobPath(jobID, "/deployment", namespace), &d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// jobPath builds a /v1/job path with an optional namespace query.
+func jobPath(id, suffix, namespace string) string {
+	path := "/v1/job/" + url.PathEscape(id) + suffix
+	if namespace != "" {
+		path += "?namespace=" + url.QueryEscape(namespace)
+	}
+	return path
+}
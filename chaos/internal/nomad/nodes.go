@@ -0,0 +1,124 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Node scheduling eligibility values.
+const (
+	NodeEligible   = "eligible"
+	NodeIneligible = "ineligible"
+)
+
+// NodeListStub is a client node entry from /v1/nodes.
+type NodeListStub struct {
+	ID                    string
+	Name                  string
+	Address               string
+	Datacenter            string
+	NodeClass             string
+	Status                string
+	StatusDescription     string
+	SchedulingEligibility string
+	Drain                 bool
+}
+
+// Nodes lists the client nodes known to the cluster.
+func (c *Client) Nodes(ctx context.Context) ([]NodeListStub, error) {
+	var nodes []NodeListStub
+	if err := c.Get(ctx, "/v1/nodes", &nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// Node is a client node as returned by /v1/node/:id.
+type Node struct {
+	ID                    string
+	Name                  string
+	Datacenter            string
+	NodeClass             string
+	HTTPAddr              string
+	Status                string
+	StatusDescription     string
+	SchedulingEligibility string
+	DrainStrategy         *DrainSpec // set while the node is draining
+	Attributes            map[string]string
+	Meta                  map[string]string
+	Drivers               map[string]*DriverInfo
+}
+
+// IP returns the node's advertised IP address.
+func (n *Node) IP() string {
+	if ip := n.Attributes["unique.network.ip-address"]; ip != "" {
+		return ip
+	}
+	return strings.Split(n.HTTPAddr, ":")[0]
+}
+
+// DriverInfo is the fingerprinted state of a task driver on a node.
+type DriverInfo struct {
+	Detected          bool
+	Healthy           bool
+	HealthDescription string
+	UpdateTime        time.Time
+}
+
+// Node reads a client node by ID.
+func (c *Client) Node(ctx context.Context, id string) (*Node, error) {
+	var node Node
+	if err := c.Get(ctx, "/v1/node/"+url.PathEscape(id), &node); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// DrainSpec describes a node drain.
+type DrainSpec struct {
+	// Deadline bounds how long allocations may take to migrate. Zero means
+	// no deadline and a negative value forces an immediate drain.
+	Deadline         time.Duration
+	IgnoreSystemJobs bool
+}
+
+// drainRequest is the body of /v1/node/:id/drain.
+type drainRequest struct {
+	DrainSpec    *DrainSpec
+	MarkEligible bool
+}
+
+// UpdateDrain starts a drain, or cancels one when spec is nil. markEligible
+// only applies when cancelling.
+func (c *Client) UpdateDrain(ctx context.Context, nodeID string, spec *DrainSpec, markEligible bool) error {
+	body := drainRequest{DrainSpec: spec, MarkEligible: markEligible}
+	if err := c.Post(ctx, "/v1/node/"+url.PathEscape(nodeID)+"/drain", body, nil); err != nil {
+		return fmt.Errorf("updating drain on %s: %w", nodeID, err)
+	}
+	return nil
+}
+
+// UpdateEligibility marks a node eligible or ineligible for scheduling.
+func (c *Client) UpdateEligibility(ctx context.Context, nodeID string, eligible bool) error {
+	value := NodeIneligible
+	if eligible {
+		value = NodeEligible
+	}
+	body := map[string]string{"Eligibility": value}
+	if err := c.Post(ctx, "/v1/node/"+url.PathEscape(nodeID)+"/eligibility", body, nil); err != nil {
+		return fmt.Errorf("updating eligibility on %s: %w", nodeID, err)
+	}
+	return nil
+}
+
+// NodeAllocations lists allocations placed on a node.
+func (c *Client) NodeAllocations(ctx context.Context, nodeID string) ([]Allocation, error) {
+	var allocs []Allocation
+	if err := c.Get(ctx, "/v1/node/"+url.PathEscape(nodeID)+"/allocations", &allocs); err != nil {
+		return nil, err
+	}
+	return allocs, nil
+}
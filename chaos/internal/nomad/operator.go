@@ -0,0 +1,152 @@
+package nomad
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RaftServer is a member of the raft configuration.
+type RaftServer struct {
+	ID           string
+	Node         string
+	Address      string // raft address, "ip:4647"
+	Leader       bool
+	Voter        bool
+	RaftProtocol string
+}
+
+// IP returns the host part of the raft address.
+func (s RaftServer) IP() string {
+	return strings.Split(s.Address, ":")[0]
+}
+
+// RaftConfiguration is the response of /v1/operator/raft/configuration.
+type RaftConfiguration struct {
+	Servers []RaftServer
+	Index   uint64
+}
+
+// ServerByIP returns the raft server with the given IP, if present.
+func (r *RaftConfiguration) ServerByIP(ip string) (RaftServer, bool) {
+	for _, s := range r.Servers {
+		if s.IP() == ip {
+			return s, true
+		}
+	}
+	return RaftServer{}, false
+}
+
+// RaftConfiguration reads the current raft configuration.
+func (c *Client) RaftConfiguration(ctx context.Context) (*RaftConfiguration, error) {
+	var cfg RaftConfiguration
+	if err := c.Get(ctx, "/v1/operator/raft/configuration", &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// RemoveRaftPeer removes a server from the raft configuration by ID.
+func (c *Client) RemoveRaftPeer(ctx context.Context, id string) error {
+	if err := c.Delete(ctx, "/v1/operator/raft/peer?id="+url.QueryEscape(id), nil); err != nil {
+		return fmt.Errorf("removing raft peer %s: %w", id, err)
+	}
+	return nil
+}
+
+// TransferLeadership asks the leader to hand leadership to the given server ID.
+func (c *Client) TransferLeadership(ctx context.Context, id string) error {
+	if err := c.Put(ctx, "/v1/operator/raft/transfer-leadership?id="+url.QueryEscape(id), nil, nil); err != nil {
+		return fmt.Errorf("transferring leadership to %s: %w", id, err)
+	}
+	return nil
+}
+
+// SnapshotSave streams a raft snapshot archive into w.
+func (c *Client) SnapshotSave(ctx context.Context, w io.Writer) (int64, error) {
+	resp, err := c.Send(ctx, http.MethodGet, "/v1/operator/snapshot", nil)
+	if err != nil {
+		return 0, fmt.Errorf("saving snapshot: %w", err)
+	}
+	defer resp.Body.Close()
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("reading snapshot: %w", err)
+	}
+	return n, nil
+}
+
+// SnapshotRestore uploads a snapshot archive, replacing the cluster state.
+func (c *Client) SnapshotRestore(ctx context.Context, r io.Reader) error {
+	if err := c.Put(ctx, "/v1/operator/snapshot", r, nil); err != nil {
+		return fmt.Errorf("restoring snapshot: %w", err)
+	}
+	return nil
+}
+
+// ServerHealth is one server's entry in the autopilot health report.
+type ServerHealth struct {
+	ID          string
+	Name        string
+	Address     string
+	SerfStatus  string
+	Version     string
+	Leader      bool
+	LastContact Duration
+	LastTerm    uint64
+	LastIndex   uint64
+	Healthy     bool
+	Voter       bool
+	StableSince string
+}
+
+// AutopilotHealth is the response of /v1/operator/autopilot/health.
+type AutopilotHealth struct {
+	Healthy          bool
+	FailureTolerance int
+	Servers          []ServerHealth
+}
+
+// AutopilotHealth reads the autopilot view of server health.
+func (c *Client) AutopilotHealth(ctx context.Context) (*AutopilotHealth, error) {
+	var health AutopilotHealth
+	if err := c.Get(ctx, "/v1/operator/autopilot/health", &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// Duration decodes durations that the API renders either as a string
+// ("12.5ms") or as integer nanoseconds, depending on the endpoint.
+type Duration time.Duration
+
+// UnmarshalJSON accepts both encodings.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*d = Duration(parsed)
+		return nil
+	}
+
+	var ns int64
+	if err := json.Unmarshal(data, &ns); err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	*d = Duration(ns)
+	return nil
+}
+
+// MarshalJSON renders the duration as a readable string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
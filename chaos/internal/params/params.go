@@ -0,0 +1,99 @@
+// Package params provides typed accessors for action and assertion arguments.
+//
+// Arguments arrive from two sources: scenario YAML (ints, floats, bools,
+// strings and lists) and CLI --arg flags (mostly strings). The accessors
+// accept either form and fall back to a default when the key is absent.
+package params
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// String returns args[key] as a string, or def if unset.
+func String(args map[string]any, key, def string) string {
+	switch v := args[key].(type) {
+	case string:
+		return v
+	case nil:
+		return def
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Int returns args[key] as an int, or def if unset or unparseable.
+func Int(args map[string]any, key string, def int) int {
+	switch v := args[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case string:
+		if i, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return i
+		}
+	}
+	return def
+}
+
+// Bool returns args[key] as a bool, or def if unset or unparseable.
+func Bool(args map[string]any, key string, def bool) bool {
+	switch v := args[key].(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+// Duration returns args[key] as a time.Duration, or def if unset.
+// Strings are parsed with time.ParseDuration; bare numbers are seconds.
+func Duration(args map[string]any, key string, def time.Duration) (time.Duration, error) {
+	switch v := args[key].(type) {
+	case nil:
+		return def, nil
+	case time.Duration:
+		return v, nil
+	case int:
+		return time.Duration(v) * time.Second, nil
+	case float64:
+		return time.Duration(v * float64(time.Second)), nil
+	case string:
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", key, v, err)
+		}
+		return d, nil
+	default:
+		return 0, fmt.Errorf("invalid %s: unsupported type %T", key, v)
+	}
+}
+
+// StringSlice returns args[key] as a list of strings. A single string is
+// split on commas, so "server-0,server-1" and a YAML list are equivalent.
+func StringSlice(args map[string]any, key string) []string {
+	var out []string
+	switch v := args[key].(type) {
+	case string:
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+	case []string:
+		out = append(out, v...)
+	case []any:
+		for _, item := range v {
+			out = append(out, fmt.Sprint(item))
+		}
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package params
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestScalars(t *testing.T) {
+	args := map[string]any{
+		"name":    "server-0",
+		"port":    4646,
+		"float":   2.0,
+		"text":    " 3 ",
+		"bad":     "three",
+		"flag":    "true",
+		"yesflag": true,
+	}
+	if got := String(args, "name", ""); got != "server-0" {
+		t.Errorf("String = %q", got)
+	}
+	if got := String(args, "port", ""); got != "4646" {
+		t.Errorf("String of an int = %q", got)
+	}
+	if got := String(args, "missing", "def"); got != "def" {
+		t.Errorf("String default = %q", got)
+	}
+
+	for key, want := range map[string]int{"port": 4646, "float": 2, "text": 3, "bad": 7, "missing": 7} {
+		if got := Int(args, key, 7); got != want {
+			t.Errorf("Int(%s) = %d, want %d", key, got, want)
+		}
+	}
+	for key, want := range map[string]bool{"flag": true, "yesflag": true, "bad": false, "missing": false} {
+		if got := Bool(args, key, false); got != want {
+			t.Errorf("Bool(%s) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want time.Duration
+	}{
+		{"unset", nil, time.Minute},
+		{"duration", 5 * time.Second, 5 * time.Second},
+		{"int seconds", 30, 30 * time.Second},
+		{"float seconds", 1.5, 1500 * time.Millisecond},
+		{"string", " 2m ", 2 * time.Minute},
+		{"negative", "-45s", -45 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{}
+			if tt.v != nil {
+				args["d"] = tt.v
+			}
+			got, err := Duration(args, "d", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Duration = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	for _, v := range []any{"30", "soon", true} {
+		if _, err := Duration(map[string]any{"d": v}, "d", 0); err == nil {
+			t.Errorf("Duration(%#v) succeeded", v)
+		}
+	}
+}
+
+func TestStringSlice(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want []string
+	}{
+		{"comma separated", "server-0, server-1,,", []string{"server-0", "server-1"}},
+		{"strings", []string{"a,b"}, []string{"a,b"}},
+		{"YAML list", []any{"a", 1}, []string{"a", "1"}},
+		{"unset", nil, nil},
+		{"other type", 3, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{}
+			if tt.v != nil {
+				args["nodes"] = tt.v
+			}
+			if got := StringSlice(args, "nodes"); !slices.Equal(got, tt.want) {
+				t.Errorf("StringSlice = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
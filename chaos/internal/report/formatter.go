@@ -117,6 +117,23 @@ func (r *Report) FormatMarkdown() string {
 
 	sb.WriteString("\n")
 
+	// Step details (measurements, per-node results)
+	hasDetails := false
+	for _, e := range r.Events {
+		if len(e.Details) == 0 {
+			continue
+		}
+		if !hasDetails {
+			sb.WriteString("## Details\n\n")
+			hasDetails = true
+		}
+		data, err := json.MarshalIndent(e.Details, "", "  ")
+		if err != nil {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("### %s\n\n```json\n%s\n```\n\n", e.Step, data))
+	}
+
 	if r.Error != nil {
 		sb.WriteString(fmt.Sprintf("## Error\n\n```\n%v\n```\n", r.Error))
 	}
@@ -13,9 +13,17 @@ import (
 
 // Runner executes scenarios.
 type Runner struct {
-	driver  driver.Driver
-	cluster *driver.Cluster
-	verbose bool
+	driver       driver.Driver
+	cluster      *driver.Cluster
+	verbose      bool
+	artifactDir  string
+	autoSnapshot bool
+
+	// started is when the current scenario began
+	started time.Time
+
+	// executed tracks action contexts for cleanup and step history
+	executed []*driver.ActionContext
 }
 
 // NewRunner creates a new scenario runner.
@@ -27,6 +35,16 @@ func NewRunner(drv driver.Driver, cluster *driver.Cluster, verbose bool) *Runner
 	}
 }
 
+// SetArtifactDir sets where actions store files kept with the run.
+func (r *Runner) SetArtifactDir(dir string) {
+	r.artifactDir = dir
+}
+
+// SetAutoSnapshot enables a raft snapshot before scenarios tagged "destructive".
+func (r *Runner) SetAutoSnapshot(enabled bool) {
+	r.autoSnapshot = enabled
+}
+
 // Run executes a scenario and returns a report.
 func (r *Runner) Run(ctx context.Context, scenario *Scenario) (*report.Report, error) {
 	rep := report.NewReport(scenario.Name)
@@ -40,12 +58,21 @@ func (r *Runner) Run(ctx context.Context, scenario *Scenario) (*report.Report, e
 	}
 
 	// Track executed actions for cleanup
-	var executedActions []*driver.ActionContext
+	r.executed = nil
+	r.started = time.Now()
 
 	// Execute steps
 	r.log("Starting scenario: %s", scenario.Name)
 	runCleanup := false
 
+	if r.autoSnapshot && scenario.HasTag(TagDestructive) {
+		if err := r.takeSafetySnapshot(ctx, rep); err != nil {
+			rep.Success = false
+			rep.Error = err
+			return rep, err
+		}
+	}
+
 	for i, step := range scenario.Steps {
 		select {
 		case <-ctx.Done():
@@ -68,7 +95,7 @@ func (r *Runner) Run(ctx context.Context, scenario *Scenario) (*report.Report, e
 
 		result, actx, err := r.executeStep(ctx, &step)
 		if actx != nil {
-			executedActions = append(executedActions, actx)
+			r.executed = append(r.executed, actx)
 		}
 
 		rep.AddEvent(report.Event{
@@ -77,6 +104,7 @@ func (r *Runner) Run(ctx context.Context, scenario *Scenario) (*report.Report, e
 			Step:     step.Name,
 			Message:  result.Message,
 			Duration: result.Duration,
+			Details:  result.Details,
 		})
 
 		if err != nil || !result.Success {
@@ -91,20 +119,45 @@ func (r *Runner) Run(ctx context.Context, scenario *Scenario) (*report.Report, e
 				r.log("  Step failed, aborting: %v", err)
 				rep.Success = false
 				rep.Error = err
-				r.runCleanup(ctx, scenario.Cleanup, executedActions, rep)
+				r.runCleanup(ctx, scenario.Cleanup, r.executed, rep)
 				return rep, err
 			}
 		}
 	}
 
 	// Run cleanup steps
-	r.runCleanup(ctx, scenario.Cleanup, executedActions, rep)
+	r.runCleanup(ctx, scenario.Cleanup, r.executed, rep)
 
 	rep.Success = true
 	r.log("Scenario completed successfully")
 	return rep, nil
 }
 
+// takeSafetySnapshot saves a raft snapshot before a destructive scenario so
+// restore-snapshot steps (or an operator) have a known-good state to return to.
+func (r *Runner) takeSafetySnapshot(ctx context.Context, rep *report.Report) error {
+	r.log("Taking safety snapshot before destructive scenario")
+
+	step := &Step{Name: "auto-snapshot", Action: "snapshot"}
+	result, actx, err := r.executeAction(ctx, step)
+	if actx != nil {
+		r.executed = append(r.executed, actx)
+	}
+
+	rep.AddEvent(report.Event{
+		Time:    time.Now(),
+		Type:    result.EventType(),
+		Step:    step.Name,
+		Message: result.Message,
+		Details: result.Details,
+	})
+
+	if err != nil {
+		return fmt.Errorf("safety snapshot: %w", err)
+	}
+	return nil
+}
+
 // executeStep runs a single step.
 func (r *Runner) executeStep(ctx context.Context, step *Step) (*StepResult, *driver.ActionContext, error) {
 	// Apply step timeout
@@ -141,6 +194,7 @@ func (r *Runner) executeStep(ctx context.Context, step *Step) (*StepResult, *dri
 		}
 
 		if attempt < retries {
+			r.undoAttempt(ctx, actx)
 			r.log("  Retry %d/%d after failure", attempt, retries)
 			time.Sleep(time.Second)
 		}
@@ -150,6 +204,28 @@ func (r *Runner) executeStep(ctx context.Context, step *Step) (*StepResult, *dri
 	return result, actx, err
 }
 
+// undoAttempt rolls back what a failed action attempt applied before the
+// step is retried, so attempts do not stack faults. An attempt whose
+// rollback fails is kept for the rollback at the end of the scenario.
+func (r *Runner) undoAttempt(ctx context.Context, actx *driver.ActionContext) {
+	if actx == nil {
+		return
+	}
+	actionName, _ := actx.State["_action_name"].(string)
+	if actionName == "" {
+		return
+	}
+
+	action, err := actions.Get(actionName)
+	if err == nil {
+		err = action.Rollback(ctx, actx)
+	}
+	if err != nil {
+		r.log("  Rolling back failed attempt: %v", err)
+		r.executed = append(r.executed, actx)
+	}
+}
+
 // executeAction runs an action step.
 func (r *Runner) executeAction(ctx context.Context, step *Step) (*StepResult, *driver.ActionContext, error) {
 	action, err := actions.Get(step.Action)
@@ -158,15 +234,26 @@ func (r *Runner) executeAction(ctx context.Context, step *Step) (*StepResult, *d
 	}
 
 	actx := driver.NewActionContext(r.driver, r.cluster)
+	actx.History = append([]*driver.ActionContext(nil), r.executed...)
+	actx.ArtifactDir = r.artifactDir
+	actx.StartedAt = time.Now()
 
 	if err := action.Execute(ctx, actx, step.Args); err != nil {
-		return &StepResult{Success: false, Message: err.Error()}, actx, err
+		// Roll back partially applied faults if the action recorded any state
+		if len(actx.State) > 0 {
+			actx.State["_action_name"] = step.Action
+		}
+		return &StepResult{Success: false, Message: err.Error(), Details: copyDetails(actx.Details)}, actx, err
 	}
 
 	// Store action name for rollback
 	actx.State["_action_name"] = step.Action
 
-	return &StepResult{Success: true, Message: fmt.Sprintf("Executed %s", step.Action)}, actx, nil
+	return &StepResult{
+		Success: true,
+		Message: fmt.Sprintf("Executed %s", step.Action),
+		Details: copyDetails(actx.Details),
+	}, actx, nil
 }
 
 // executeAssert runs an assertion step.
@@ -177,13 +264,15 @@ func (r *Runner) executeAssert(ctx context.Context, step *Step) (*StepResult, er
 	}
 
 	actx := driver.NewAssertContext(r.driver, r.cluster)
+	actx.History = append([]*driver.ActionContext(nil), r.executed...)
+	actx.ScenarioStart = r.started
 
 	result, err := assertion.Check(ctx, actx, step.Args)
 	if err != nil {
 		return &StepResult{Success: false, Message: err.Error()}, err
 	}
 
-	return &StepResult{Success: result.Success, Message: result.Message}, nil
+	return &StepResult{Success: result.Success, Message: result.Message, Details: result.Details}, nil
 }
 
 // executeWait runs a wait step.
@@ -242,6 +331,7 @@ func (r *Runner) runCleanup(ctx context.Context, cleanupSteps []Step, executedAc
 				Type:    report.EventError,
 				Step:    fmt.Sprintf("rollback-%s", actionName),
 				Message: err.Error(),
+				Details: copyDetails(actx.Details),
 			})
 		} else {
 			rep.AddEvent(report.Event{
@@ -249,6 +339,7 @@ func (r *Runner) runCleanup(ctx context.Context, cleanupSteps []Step, executedAc
 				Type:    report.EventCleanup,
 				Step:    fmt.Sprintf("rollback-%s", actionName),
 				Message: "Rollback successful",
+				Details: copyDetails(actx.Details),
 			})
 		}
 	}
@@ -260,11 +351,25 @@ func (r *Runner) log(format string, args ...any) {
 	}
 }
 
+// copyDetails snapshots a details map so later rollback observations
+// don't leak into the event recorded for the original step.
+func copyDetails(details map[string]any) map[string]any {
+	if len(details) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(details))
+	for k, v := range details {
+		out[k] = v
+	}
+	return out
+}
+
 // StepResult captures the outcome of a step execution.
 type StepResult struct {
 	Success  bool
 	Message  string
 	Duration time.Duration
+	Details  map[string]any
 }
 
 // EventType returns the appropriate event type for this result.
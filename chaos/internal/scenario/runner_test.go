@@ -0,0 +1,73 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/libvirt-standalone/chaos/internal/actions"
+	"github.com/libvirt-standalone/chaos/internal/driver"
+)
+
+// flakyAction applies a partial fault and fails until its last attempt.
+type flakyAction struct {
+	failures    int
+	rollbackErr error
+	attempts    int
+	rolledBack  []int
+}
+
+func (a *flakyAction) Name() string        { return "test-flaky" }
+func (a *flakyAction) Description() string { return "fails before succeeding" }
+
+func (a *flakyAction) Execute(_ context.Context, actx *driver.ActionContext, _ map[string]any) error {
+	a.attempts++
+	actx.State["attempt"] = a.attempts
+	if a.attempts <= a.failures {
+		return errors.New("partially applied")
+	}
+	return nil
+}
+
+func (a *flakyAction) Rollback(_ context.Context, actx *driver.ActionContext) error {
+	a.rolledBack = append(a.rolledBack, actx.State["attempt"].(int))
+	return a.rollbackErr
+}
+
+var flaky = &flakyAction{}
+
+func init() {
+	if err := actions.Register(flaky); err != nil {
+		panic(err)
+	}
+}
+
+func TestRetriedActionRollsBackFailedAttempts(t *testing.T) {
+	step := &Step{Name: "flaky", Action: "test-flaky", Retries: 2}
+
+	*flaky = flakyAction{failures: 1}
+	r := NewRunner(nil, &driver.Cluster{}, false)
+	result, actx, err := r.executeStep(context.Background(), step)
+	if err != nil || !result.Success {
+		t.Fatalf("step failed: %v", err)
+	}
+	if got := actx.State["attempt"]; got != 2 {
+		t.Errorf("returned attempt %v, want 2", got)
+	}
+	if len(flaky.rolledBack) != 1 || flaky.rolledBack[0] != 1 {
+		t.Errorf("rolled back attempts %v before retrying, want [1]", flaky.rolledBack)
+	}
+	if len(r.executed) != 0 {
+		t.Errorf("kept %d attempts for the final rollback, want none", len(r.executed))
+	}
+
+	// An attempt that cannot be undone now is left for the final rollback
+	*flaky = flakyAction{failures: 1, rollbackErr: errors.New("still faulted")}
+	r = NewRunner(nil, &driver.Cluster{}, false)
+	if _, _, err := r.executeStep(context.Background(), step); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.executed) != 1 || r.executed[0].State["attempt"] != 1 {
+		t.Errorf("executed = %v, want the first attempt", r.executed)
+	}
+}
@@ -66,6 +66,19 @@ func (d Duration) Duration() time.Duration {
 	return time.Duration(d)
 }
 
+// TagDestructive marks scenarios that may destroy cluster state.
+const TagDestructive = "destructive"
+
+// HasTag reports whether the scenario carries the given tag.
+func (s *Scenario) HasTag(tag string) bool {
+	for _, t := range s.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
 // StepType returns the type of step based on which field is set.
 func (s *Step) StepType() string {
 	switch {
@@ -0,0 +1,22 @@
+// Package vault provides a minimal client for the Vault HTTP API.
+package vault
+
+import (
+	"github.com/libvirt-standalone/chaos/internal/httpapi"
+)
+
+// Client talks to a single Vault server.
+type Client struct {
+	*httpapi.Client
+}
+
+// NewClient creates a client from configuration. The token is sent as
+// X-Vault-Token.
+func NewClient(cfg httpapi.Config) (*Client, error) {
+	cfg.TokenHeader = "X-Vault-Token"
+	c, err := httpapi.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{Client: c}, nil
+}
@@ -0,0 +1,109 @@
+// Package virt inspects libvirt domains created by nomad-driver-virt on
+// client nodes.
+package virt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/libvirt-standalone/chaos/internal/driver"
+)
+
+// Domain is a libvirt domain on a client node.
+type Domain struct {
+	Name  string `json:"name"`
+	UUID  string `json:"uuid"`
+	State string `json:"state"` // virsh domstate with spaces removed, e.g. "running", "shutoff"
+}
+
+// Running reports whether the domain is running.
+func (d Domain) Running() bool {
+	return d.State == "running"
+}
+
+// AllocDomain ties a domain to the allocation, task and client node it
+// belongs to.
+type AllocDomain struct {
+	AllocID string `json:"alloc_id"`
+	Task    string `json:"task"`
+	Node    string `json:"node"`
+	Domain
+}
+
+// TaskID identifies the task as alloc-id/task.
+func (d AllocDomain) TaskID() string {
+	return d.AllocID + "/" + d.Task
+}
+
+// Virsh runs virsh against the system libvirt daemon.
+const Virsh = "virsh -c qemu:///system"
+
+// DriverName is the task driver that runs tasks as libvirt domains.
+const DriverName = "nomad-driver-virt"
+
+// VirtTasks returns the sorted names of the tasks in drivers, as returned
+// by nomad.Allocation.TaskDrivers, that run under the virt driver.
+func VirtTasks(drivers map[string]string) []string {
+	var tasks []string
+	for task, name := range drivers {
+		if name == DriverName {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Strings(tasks)
+	return tasks
+}
+
+// TaskDomains returns the domains of an allocation that belong to task.
+// The driver names domains after the task, so with several virt tasks in
+// the allocation a domain belongs to the task its name contains; with a
+// single virt task every domain of the allocation is that task's.
+func TaskDomains(domains []Domain, task string, virtTasks int) []Domain {
+	if virtTasks <= 1 {
+		return domains
+	}
+	var out []Domain
+	for _, d := range domains {
+		if strings.Contains(d.Name, task) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// AllocDomains lists the domains, in any state, that belong to an
+// allocation. A domain matches when its name contains the allocation ID's
+// first segment or its XML (disk and cloud-init paths under the alloc dir)
+// mentions the full ID.
+func AllocDomains(ctx context.Context, ssh driver.SSHClient, allocID string) ([]Domain, error) {
+	short := strings.SplitN(allocID, "-", 2)[0]
+	script := fmt.Sprintf(`for d in $(%[1]s list --all --name); do
+  case $d in *%[2]s*) ;; *) %[1]s dumpxml $d | grep -qF %[3]s || continue;; esac
+  echo $d $(%[1]s domuuid $d) $(%[1]s domstate $d | tr -d " ")
+done`, Virsh, short, allocID)
+
+	stdout, stderr, code, err := ssh.RunWithSudo(ctx, fmt.Sprintf("sh -c '%s'", script))
+	if err != nil {
+		return nil, err
+	}
+	if code != 0 {
+		return nil, fmt.Errorf("listing domains exited %d: %s", code, strings.TrimSpace(stderr))
+	}
+
+	var domains []Domain
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		domains = append(domains, Domain{Name: fields[0], UUID: fields[1], State: fields[2]})
+	}
+	return domains, nil
+}
+
+// PIDFile is where libvirt records the qemu process of a running domain.
+func PIDFile(domain string) string {
+	return "/run/libvirt/qemu/" + domain + ".pid"
+}
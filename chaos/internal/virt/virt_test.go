@@ -0,0 +1,47 @@
+package virt
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestVirtTasks(t *testing.T) {
+	drivers := map[string]string{
+		"vm-b":    DriverName,
+		"sidecar": "docker",
+		"vm-a":    DriverName,
+	}
+	if got, want := VirtTasks(drivers), []string{"vm-a", "vm-b"}; !slices.Equal(got, want) {
+		t.Errorf("VirtTasks = %v, want %v", got, want)
+	}
+}
+
+func TestTaskDomains(t *testing.T) {
+	domains := []Domain{
+		{Name: "web-1a2b3c4d", UUID: "u1", State: "running"},
+		{Name: "db-1a2b3c4d", UUID: "u2", State: "running"},
+	}
+
+	tests := []struct {
+		name      string
+		task      string
+		virtTasks int
+		want      []string
+	}{
+		{"single virt task owns every domain", "web", 1, []string{"web-1a2b3c4d", "db-1a2b3c4d"}},
+		{"matched by task name", "web", 2, []string{"web-1a2b3c4d"}},
+		{"other task", "db", 2, []string{"db-1a2b3c4d"}},
+		{"no domain for task", "cache", 3, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range TaskDomains(domains, tt.task, tt.virtTasks) {
+				got = append(got, d.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("TaskDomains(%q, %d) = %v, want %v", tt.task, tt.virtTasks, got, tt.want)
+			}
+		})
+	}
+}